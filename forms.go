@@ -2,27 +2,47 @@ package trans
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"regexp"
+	"strings"
 )
 
+type ChoiceAttachment struct {
+	Type string `json:"type,omitempty"`
+	Href string `json:"href,omitempty"`
+}
+
 type FieldChoice struct {
-	ID    string `json:"id,omitempty"`
-	Label string `json:"label,omitempty"`
-	Ref   string `json:"ref,omitempty"`
+	ID         string            `json:"id,omitempty"`
+	Label      string            `json:"label,omitempty"`
+	Ref        string            `json:"ref,omitempty"`
+	Attachment *ChoiceAttachment `json:"attachment,omitempty"`
 }
 
 type FieldProperties struct {
-	Choices     []*FieldChoice `json:"choices,omitempty"`
-	Description string         `json:"description,omitempty"`
+	Choices                []*FieldChoice `json:"choices,omitempty"`
+	Description            string         `json:"description,omitempty"`
+	AllowMultipleSelection bool           `json:"allow_multiple_selection,omitempty"`
+	AllowOtherChoice       bool           `json:"allow_other_choice,omitempty"`
+	Steps                  int            `json:"steps,omitempty"`
+	StartAtOne             bool           `json:"start_at_one,omitempty"`
+	Randomize              bool           `json:"randomize,omitempty"`
+	AlphabeticalOrder      bool           `json:"alphabetical_order,omitempty"`
+}
+
+type FieldValidations struct {
+	Required bool `json:"required,omitempty"`
+	MinValue *int `json:"min_value,omitempty"`
+	MaxValue *int `json:"max_value,omitempty"`
 }
 
 type Field struct {
-	ID         string           `json:"id,omitempty"`
-	Type       string           `json:"type,omitempty"`
-	Title      string           `json:"title,omitempty"`
-	Ref        string           `json:"ref,omitempty"`
-	Properties *FieldProperties `json:"properties,omitempty"`
+	ID          string            `json:"id,omitempty"`
+	Type        string            `json:"type,omitempty"`
+	Title       string            `json:"title,omitempty"`
+	Ref         string            `json:"ref,omitempty"`
+	Properties  *FieldProperties  `json:"properties,omitempty"`
+	Validations *FieldValidations `json:"validations,omitempty"`
 }
 
 type Workspace struct {
@@ -33,57 +53,138 @@ type Form struct {
 	Workspace       *Workspace      `json:"workspace,omitempty"`
 	Title           string          `json:"title"`
 	Fields          []*Field        `json:"fields"`
+	WelcomeScreens  []*Field        `json:"welcome_screens,omitempty"`
 	ThankYouScreens []*Field        `json:"thankyou_screens,omitempty"`
 	Logic           json.RawMessage `json:"logic,omitempty"`
 }
 
+const (
+	KindMapping = "mapping"
+	KindNumber  = "number"
+)
+
+// Strategies used to match a field to its destination field
+const (
+	MatchByShape = "shape"
+	MatchByRef   = "ref"
+	MatchByID    = "id"
+	MatchHybrid  = "hybrid"
+
+	MatchByOverride = "override"
+
+	// choices without refs or IDs in common are paired by position
+	MatchByPosition = "position"
+)
+
+// ChoiceTranslation pairs a choice of the source field
+// with the choice of the destination field. Value is the text
+// of the source option the label stands for, if it has one.
+type ChoiceTranslation struct {
+	Ref       string `json:"ref,omitempty"`
+	Label     string `json:"label"`
+	DestRef   string `json:"dest_ref,omitempty"`
+	DestLabel string `json:"dest_label"`
+	MatchedBy string `json:"matched_by,omitempty"`
+	Value     string `json:"value,omitempty"`
+}
+
 type FieldTranslator struct {
-	Translate bool              `json:"translate"`
-	Mapping   map[string]string `json:"mapping,omitempty"`
+	Translate         bool                 `json:"translate"`
+	Kind              string               `json:"kind,omitempty"`
+	Mapping           map[string]string    `json:"mapping,omitempty"`
+	MultipleSelection bool                 `json:"multiple_selection,omitempty"`
+	AllowOther        bool                 `json:"allow_other,omitempty"`
+	Normalization     []string             `json:"normalization,omitempty"`
+	Fuzzy             bool                 `json:"fuzzy,omitempty"`
+	FieldType         string               `json:"field_type,omitempty"`
+	Min               *int                 `json:"min,omitempty"`
+	Max               *int                 `json:"max,omitempty"`
+	DestRef           string               `json:"dest_ref,omitempty"`
+	MatchedBy         string               `json:"matched_by,omitempty"`
+	Choices           []*ChoiceTranslation `json:"choices,omitempty"`
 }
 
 type FormTranslator struct {
 	Fields map[string]*FieldTranslator `json:"fields"`
 }
 
+// Answer is a response to a field and the value it stands for.
+// Raw is the line of question text the answer was extracted from,
+// before normalization, if normalizing changed it.
 type Answer struct {
 	Response string
 	Value    string
+	Source   string
+	Raw      string
 }
 
+// Reasons a field could not be translated
+const (
+	ReasonMissingField    = "missing_field"
+	ReasonNoChoices       = "no_choices"
+	ReasonChoiceCount     = "choice_count"
+	ReasonLabelExtraction = "label_extraction"
+	ReasonInvalidField    = "invalid_field"
+	ReasonInvalidOptions  = "invalid_options"
+	ReasonShape           = "shape"
+	ReasonLogic           = "logic"
+	ReasonChoiceMismatch  = "choice_mismatch"
+	ReasonChoiceOrder     = "choice_order"
+	ReasonNormalization   = "normalization"
+)
+
 type FormTranslationError struct {
 	Message string
+	Reason  string
+	Err     error
 }
 
 func (e *FormTranslationError) Error() string {
 	return e.Message
 }
 
-func ExtractLabels(options string) ([]*Answer, error) {
-	character := `[\p{L}0-9]` // [\p{L}] for unicode? Only caps?
-	base := `(?:^|\n)(?:- ?(%s)(?:[^\S\r\n]|[\p{Pd}-\.\)])+|(%s)[\p{Pd}-\.\)]+[^\S\r\n]?)([^\n]+)`
-	r, _ := regexp.Compile(fmt.Sprintf(base, character, character))
-	matches := r.FindAllStringSubmatch(options, -1)
+func (e *FormTranslationError) Unwrap() error {
+	return e.Err
+}
 
-	answers := []*Answer{}
+func reasonOf(err error, def string) string {
+	var fe *FormTranslationError
+	if errors.As(err, &fe) && fe.Reason != "" {
+		return fe.Reason
+	}
+	return def
+}
 
-	for _, match := range matches {
-		if len(match) != 4 {
-			return answers, fmt.Errorf("Could not make labels from options: %s", options)
-		}
-		if match[1] == "" && match[2] == "" {
-			return answers, fmt.Errorf("Could not make labels from options: %s", options)
-		}
-		if match[1] != "" {
-			answers = append(answers, &Answer{match[1], match[3]})
-		}
-		if match[2] != "" {
-			answers = append(answers, &Answer{match[2], match[3]})
-		}
+// FieldTranslationError is a failure to translate the field
+// at Index of the given Section of the source form.
+type FieldTranslationError struct {
+	Ref     string
+	Section string
+	Index   int
+	Reason  string
+	Err     error
+}
 
-	}
+func (e *FieldTranslationError) Error() string {
+	return fmt.Sprintf("Field %v (%v index %v): %v", e.Ref, e.Section, e.Index, e.Err.Error())
+}
 
-	return answers, nil
+func (e *FieldTranslationError) Unwrap() error {
+	return e.Err
+}
+
+// FormTranslationErrors collects every field that failed
+// when building a FormTranslator with CollectErrors.
+type FormTranslationErrors struct {
+	Errors []*FieldTranslationError
+}
+
+func (e *FormTranslationErrors) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return fmt.Sprintf("Could not translate %v fields:\n%v", len(e.Errors), strings.Join(msgs, "\n"))
 }
 
 func mapResponse(ans []*Answer) []string {
@@ -108,94 +209,478 @@ func compare(a, b []string) bool {
 	return true
 }
 
+// Where the answers of a field come from
+const (
+	AnswerSourceChoices     = "choices"
+	AnswerSourceTitle       = "title"
+	AnswerSourceDescription = "description"
+)
+
+// ExtractOptions configure how answers are extracted from a field.
+// Lettered, when set, overrides the detection of whether the choices
+// are labels of options listed in the question text. Location is
+// where to look for the options, AnswerSourceTitle or
+// AnswerSourceDescription, or, if not set, the title and then
+// the description. Variables are the values of the placeholders
+// in the text, see NormalizeText.
+type ExtractOptions struct {
+	Grammar   *LabelGrammar
+	Lettered  *bool
+	Location  string
+	Variables map[string]string
+}
+
 func ExtractAnswers(field *Field) ([]*Answer, error) {
-	choices := field.Properties.Choices
-	N := len(choices)
+	return ExtractAnswersWith(field, &ExtractOptions{})
+}
+
+// hasMarkers is true if any of the labels extracted
+// from the text is a label of the choices.
+func hasMarkers(labels []string, extracted []*Answer) bool {
+	for _, a := range extracted {
+		for _, l := range labels {
+			if a.Response == l {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type fieldText struct {
+	source string
+	text   string
+	raw    string
+}
+
+func newFieldText(source, raw string, variables map[string]string) *fieldText {
+	return &fieldText{source, NormalizeText(raw, variables), raw}
+}
 
-	if N == 0 {
-		return nil, &FormTranslationError{fmt.Sprintf("Multiple Choice question with no answer options! Ref: %v", field.Ref)}
+func fieldTexts(field *Field, location string, variables map[string]string) ([]*fieldText, error) {
+	description := ""
+	if field.Properties != nil {
+		description = field.Properties.Description
 	}
+	title := newFieldText(AnswerSourceTitle, field.Title, variables)
+	descriptionText := newFieldText(AnswerSourceDescription, description, variables)
+
+	switch location {
+	case "":
+		return []*fieldText{title, descriptionText}, nil
+	case AnswerSourceTitle:
+		return []*fieldText{title}, nil
+	case AnswerSourceDescription:
+		return []*fieldText{descriptionText}, nil
+	}
+	return nil, &FormTranslationError{Message: fmt.Sprintf("Unknown answer location: %v", location), Reason: ReasonInvalidOptions}
+}
+
+func ExtractAnswersWith(field *Field, eo *ExtractOptions) ([]*Answer, error) {
+	if field.Properties == nil || len(field.Properties.Choices) == 0 {
+		return nil, &FormTranslationError{Message: fmt.Sprintf("Choice question with no answer options! Ref: %v", field.Ref), Reason: ReasonNoChoices}
+	}
+
+	choices := field.Properties.Choices
+	N := len(choices)
 
 	labels := make([]string, N)
-	for i, c := range field.Properties.Choices {
+	for i, c := range choices {
 		labels[i] = c.Label
 	}
 
-	// NOTE: this is how we test if we should search the
-	// text for the answer. It's pretty hokey!
-	// add metadata to description?
+	grammar := eo.Grammar
+	if grammar == nil {
+		grammar = DefaultLabelGrammar
+	}
+
+	texts, err := fieldTexts(field, eo.Location, eo.Variables)
+	if err != nil {
+		return nil, err
+	}
 
-	// another option - try to extractlabels and if it fails with an error
-	// make it none? This is elegant but doesn't help formatting issues.
-	shortened := labels[0] == "A"
 	answers := make([]*Answer, N)
 
-	if shortened {
-		a, err := ExtractLabels(field.Title)
+	// We search the text for the answers if the choice labels
+	// are a sequence, such as A, B, C or 1, 2, 3, and the text
+	// has markers for them, as a lone "A" or a "1" can just
+	// as well be the answer itself.
+	_, lettered := LabelSequence(labels)
+	text := texts[0]
+	a, lines, err := grammar.extract(text.text)
+
+	for _, t := range texts {
+		ta, tl, terr := grammar.extract(t.text)
+		if hasMarkers(labels, ta) {
+			text, a, lines, err = t, ta, tl, terr
+			break
+		}
+	}
+	lettered = lettered && hasMarkers(labels, a)
+
+	if eo.Lettered != nil {
+		lettered = *eo.Lettered
+	}
+
+	if lettered {
 		if err != nil {
 			return answers, err
 		}
 
 		if !compare(labels, mapResponse(a)) {
-			return answers, fmt.Errorf("Problem extracting values for label: %s from question: %s", labels, field.Title)
+			return answers, fmt.Errorf("Problem extracting values for label: %s from question %s: %s", labels, text.source, text.text)
 
 		}
 
+		rawLines := strings.Split(text.raw, "\n")
+		textLines := strings.Split(text.text, "\n")
+		for i, ans := range a {
+			ans.Source = text.source
+			if l := lines[i]; rawLines[l] != textLines[l] {
+				ans.Raw = rawLines[l]
+			}
+		}
 		return a, nil
 	}
 
 	for i, label := range labels {
-		answers[i] = &Answer{label, label}
+		answers[i] = &Answer{Response: label, Value: label, Source: AnswerSourceChoices}
 	}
 	return answers, nil
 
 }
 
-func MakeMCTranslator(src *Field, dst *Field) (map[string]string, error) {
+// NOTE: multiple choice and dropdown fields share the same choice
+// structure, so both are paired by position, including the lettered
+// "A. foo" title convention handled by ExtractAnswers.
+func makeChoiceTranslator(src *Field, dst *Field, opts *TranslatorOptions) (map[string]string, []*ChoiceTranslation, error) {
 	fields := []*Field{src, dst}
 	ans := make([][]*Answer, len(fields))
 
 	for i, f := range fields {
-		a, err := ExtractAnswers(f)
+		eo, err := opts.extractOptions(f, i == 1)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		a, err := ExtractAnswersWith(f, eo)
 		if err != nil {
 
-			// TODO: keep old error - multierr
-			e := &FormTranslationError{fmt.Sprintf("Could not create translator for field %v to field %v. Had error: %v", src.Ref, dst.Ref, err.Error())}
-			return nil, e
+			e := &FormTranslationError{
+				Message: fmt.Sprintf("Could not create translator for field %v to field %v. Had error: %v", src.Ref, dst.Ref, err.Error()),
+				Reason:  reasonOf(err, ReasonLabelExtraction),
+				Err:     err,
+			}
+			return nil, nil, e
 		}
 		ans[i] = a
 	}
 
 	if len(ans[0]) != len(ans[1]) {
-		return nil, &FormTranslationError{fmt.Sprintf("Could not create translator for field %v to field %v. They had different length answers!", src.Ref, dst.Ref)}
+		return nil, nil, &FormTranslationError{Message: fmt.Sprintf("Could not create translator for field %v to field %v. They had different length answers!", src.Ref, dst.Ref), Reason: ReasonChoiceCount}
+	}
+
+	// NOTE: if only one field has options in its text, and the other
+	// one's choices are still bare labels, the grammar most likely
+	// can't read its markers, and pairing the options with the bare
+	// labels would translate them to the labels.
+	if lettered(ans[0]) != lettered(ans[1]) {
+		bare := ans[0]
+		if lettered(ans[0]) {
+			bare = ans[1]
+		}
+		if _, ok := LabelSequence(mapResponse(bare)); ok {
+			return nil, nil, &FormTranslationError{Message: fmt.Sprintf("Could not create translator for field %v to field %v. Only one of them had options in its text!", src.Ref, dst.Ref), Reason: ReasonLabelExtraction}
+		}
+	}
+
+	// answers are in the same order as the choices
+	idx, matchedBy, err := matchChoices(src, dst, src.Properties.Choices, dst.Properties.Choices)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	m := make(map[string]string)
+	paired := make([]*FieldChoice, len(idx))
 
 	for i, sa := range ans[0] {
-		da := ans[1][i]
+		da := ans[1][idx[i]]
 		m[sa.Response] = da.Value
+		paired[i] = dst.Properties.Choices[idx[i]]
+	}
+
+	choices := pairChoices(src.Properties.Choices, paired, matchedBy)
+	for i, sa := range ans[0] {
+		if sa.Value != sa.Response {
+			choices[i].Value = sa.Value
+		}
+	}
+	return m, choices, nil
+}
+
+// lettered is true if the answers were extracted from the text of the
+// field, rather than being its choices.
+func lettered(answers []*Answer) bool {
+	return len(answers) > 0 && answers[0].Source != AnswerSourceChoices
+}
+
+func MakeMCTranslator(src *Field, dst *Field) (map[string]string, error) {
+	m, _, err := makeChoiceTranslator(src, dst, &TranslatorOptions{})
+	return m, err
+}
+
+func MakeDropdownTranslator(src *Field, dst *Field) (map[string]string, error) {
+	m, _, err := makeChoiceTranslator(src, dst, &TranslatorOptions{})
+	return m, err
+}
+
+func pairChoices(src, dst []*FieldChoice, matchedBy string) []*ChoiceTranslation {
+	choices := make([]*ChoiceTranslation, len(src))
+	for i, c := range src {
+		choices[i] = &ChoiceTranslation{Ref: c.Ref, Label: c.Label, DestRef: dst[i].Ref, DestLabel: dst[i].Label, MatchedBy: matchedBy}
+	}
+	return choices
+}
+
+// choiceIndexesBy returns, for each source choice, the index of the
+// destination choice with the same key. It returns nil if the choices
+// share no keys, and false if they share only some.
+func choiceIndexesBy(src, dst []*FieldChoice, key func(*FieldChoice) string) ([]int, bool) {
+	byKey := make(map[string]int, len(dst))
+	for i, c := range dst {
+		if k := key(c); k != "" {
+			byKey[k] = i
+		}
+	}
+
+	idx := make([]int, len(src))
+	used := map[int]bool{}
+	shared := 0
+	for i, c := range src {
+		j, ok := byKey[key(c)]
+		if key(c) == "" || !ok || used[j] {
+			continue
+		}
+		idx[i] = j
+		used[j] = true
+		shared++
+	}
+
+	switch shared {
+	case 0:
+		return nil, true
+	case len(src):
+		return idx, true
+	}
+	return nil, false
+}
+
+// matchChoices pairs the choices of two fields by ref, then by ID,
+// then by position, returning for each source choice the index of
+// its destination choice. The choices must be of the same length.
+// Fields that randomize or sort their choices can't be paired by
+// position, as the order isn't what respondents see.
+// NOTE: refs and IDs are generated by Typeform, so forms made
+// separately share none, while copies of a form share them all.
+// Sharing only some means the choices were changed after copying.
+func matchChoices(src, dst *Field, srcChoices, dstChoices []*FieldChoice) ([]int, string, error) {
+	keys := []struct {
+		name string
+		key  func(*FieldChoice) string
+	}{
+		{MatchByRef, func(c *FieldChoice) string { return c.Ref }},
+		{MatchByID, func(c *FieldChoice) string { return c.ID }},
+	}
+
+	for _, k := range keys {
+		idx, ok := choiceIndexesBy(srcChoices, dstChoices, k.key)
+		if !ok {
+			return nil, "", &FormTranslationError{Message: fmt.Sprintf("Could not create translator for field %v to field %v. Only some of their choices have the same %v!", src.Ref, dst.Ref, k.name), Reason: ReasonChoiceMismatch}
+		}
+		if idx != nil {
+			return idx, k.name, nil
+		}
+	}
+
+	for _, f := range []*Field{src, dst} {
+		if f.Properties != nil && (f.Properties.Randomize || f.Properties.AlphabeticalOrder) {
+			return nil, "", &FormTranslationError{Message: fmt.Sprintf("Could not create translator for field %v to field %v. Field %v shows its choices randomized or in alphabetical order, so they can't be paired by position. Give the choices the same refs or use ChoiceOverrides.", src.Ref, dst.Ref, f.Ref), Reason: ReasonChoiceOrder}
+		}
+	}
+
+	idx := make([]int, len(srcChoices))
+	for i := range idx {
+		idx[i] = i
+	}
+	return idx, MatchByPosition, nil
+}
+
+func makePictureChoiceTranslator(src *Field, dst *Field, opts *TranslatorOptions) (map[string]string, []*ChoiceTranslation, error) {
+	choices := make([][]*FieldChoice, 2)
+	for i, f := range []*Field{src, dst} {
+		if f.Properties == nil || len(f.Properties.Choices) == 0 {
+			return nil, nil, &FormTranslationError{Message: fmt.Sprintf("Could not create translator for field %v to field %v. Picture choice question with no answer options! Ref: %v", src.Ref, dst.Ref, f.Ref), Reason: ReasonNoChoices}
+		}
+		choices[i] = f.Properties.Choices
+	}
+
+	if len(choices[0]) != len(choices[1]) {
+		return nil, nil, &FormTranslationError{Message: fmt.Sprintf("Could not create translator for field %v to field %v. They had different length answers!", src.Ref, dst.Ref), Reason: ReasonChoiceCount}
+	}
+
+	idx, matchedBy, err := matchChoices(src, dst, choices[0], choices[1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m := make(map[string]string)
+	paired := make([]*FieldChoice, len(idx))
+	for i, c := range choices[0] {
+		paired[i] = choices[1][idx[i]]
+		m[c.Label] = paired[i].Label
+	}
+
+	return m, pairChoices(choices[0], paired, matchedBy), nil
+}
+
+func MakePictureChoiceTranslator(src *Field, dst *Field) (map[string]string, error) {
+	m, _, err := makePictureChoiceTranslator(src, dst, &TranslatorOptions{})
+	return m, err
+}
+
+type BooleanWords struct {
+	Yes []string `json:"yes"`
+	No  []string `json:"no"`
+}
+
+// NOTE: Typeform stores yes_no and legal answers as booleans,
+// so these are the values we translate to, whatever the language.
+const (
+	BooleanTrue  = "true"
+	BooleanFalse = "false"
+)
+
+// BooleanVocabulary holds the localized words, per language, that
+// respondents use for yes_no and legal fields. Add a language to
+// extend the words recognized by MakeBooleanTranslator.
+var BooleanVocabulary = map[string]*BooleanWords{
+	"en": {
+		Yes: []string{"Yes", "Y", "I accept", "Accept"},
+		No:  []string{"No", "N", "I don't accept", "I do not accept", "Don't accept"},
+	},
+	"es": {
+		Yes: []string{"Sí", "Si", "Acepto"},
+		No:  []string{"No", "No acepto"},
+	},
+	"fr": {
+		Yes: []string{"Oui", "J'accepte"},
+		No:  []string{"Non", "Je n'accepte pas"},
+	},
+	"hi": {
+		Yes: []string{"हाँ", "हां", "जी हाँ", "मैं स्वीकार करता हूँ", "मैं स्वीकार करती हूँ"},
+		No:  []string{"नहीं", "जी नहीं", "मैं स्वीकार नहीं करता हूँ", "मैं स्वीकार नहीं करती हूँ"},
+	},
+	"bn": {
+		Yes: []string{"হ্যাঁ", "হাঁ"},
+		No:  []string{"না"},
+	},
+	"ar": {
+		Yes: []string{"نعم", "أوافق"},
+		No:  []string{"لا", "لا أوافق"},
+	},
+}
+
+func addBooleanWord(m map[string]string, word, value string) error {
+	for _, w := range []string{word, strings.ToLower(word)} {
+		if v, ok := m[w]; ok && v != value {
+			return &FormTranslationError{Message: fmt.Sprintf("Boolean vocabulary has conflicting values for word: %v", w), Reason: ReasonInvalidField}
+		}
+		m[w] = value
+	}
+	return nil
+}
+
+func MakeBooleanTranslator(src *Field, dst *Field) (map[string]string, error) {
+	m := map[string]string{
+		BooleanTrue:  BooleanTrue,
+		BooleanFalse: BooleanFalse,
+	}
+
+	for _, words := range BooleanVocabulary {
+		for _, w := range words.Yes {
+			if err := addBooleanWord(m, w, BooleanTrue); err != nil {
+				return nil, err
+			}
+		}
+		for _, w := range words.No {
+			if err := addBooleanWord(m, w, BooleanFalse); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	return m, nil
 }
 
-var translatorMakers = map[string]func(*Field, *Field) (map[string]string, error){
-	"multiple_choice": MakeMCTranslator,
+type translatorMaker func(*Field, *Field, *TranslatorOptions) (*FieldTranslator, error)
+
+func plainMaker(fn func(*Field, *Field) (*FieldTranslator, error)) translatorMaker {
+	return func(src, dst *Field, _ *TranslatorOptions) (*FieldTranslator, error) {
+		return fn(src, dst)
+	}
+}
+
+func mappingMaker(fn func(*Field, *Field) (map[string]string, error)) translatorMaker {
+	return func(src, dst *Field, _ *TranslatorOptions) (*FieldTranslator, error) {
+		m, err := fn(src, dst)
+		if err != nil {
+			return nil, err
+		}
+		return &FieldTranslator{Translate: true, Kind: KindMapping, Mapping: m}, nil
+	}
+}
+
+func choiceMaker(fn func(*Field, *Field, *TranslatorOptions) (map[string]string, []*ChoiceTranslation, error)) translatorMaker {
+	return func(src, dst *Field, opts *TranslatorOptions) (*FieldTranslator, error) {
+		m, choices, err := fn(src, dst, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &FieldTranslator{Translate: true, Kind: KindMapping, Mapping: m, Choices: choices}, nil
+	}
+}
+
+var translatorMakers = map[string]translatorMaker{
+	"multiple_choice": choiceMaker(makeChoiceTranslator),
+	"dropdown":        choiceMaker(makeChoiceTranslator),
+	"picture_choice":  choiceMaker(makePictureChoiceTranslator),
+	"yes_no":          mappingMaker(MakeBooleanTranslator),
+	"legal":           mappingMaker(MakeBooleanTranslator),
+	"number":          plainMaker(MakeNumberTranslator),
+	"opinion_scale":   plainMaker(MakeNumberTranslator),
+	"rating":          plainMaker(MakeNumberTranslator),
+	"nps":             plainMaker(MakeNumberTranslator),
 }
 
 func MakeFieldTranslator(field, destField *Field) (*FieldTranslator, error) {
+	return makeFieldTranslator(field, destField, &TranslatorOptions{})
+}
+
+func makeFieldTranslator(field, destField *Field, opts *TranslatorOptions) (*FieldTranslator, error) {
 	tm, ok := translatorMakers[field.Type]
 	if ok {
-		translator, err := tm(field, destField)
+		translator, err := tm(field, destField, opts)
 		if err != nil {
 			return nil, err
 		}
-		return &FieldTranslator{true, translator}, nil
+		translator.MultipleSelection = field.Properties != nil && field.Properties.AllowMultipleSelection
+		translator.AllowOther = field.Properties != nil && field.Properties.AllowOtherChoice
+		translator.FieldType = field.Type
+		return translator, nil
 	}
 
 	// NOTE: unrecognized types not dealt with here.
-	return &FieldTranslator{false, nil}, nil
+	return &FieldTranslator{Translate: false, FieldType: field.Type}, nil
 }
 
 func findField(ref string, form *Form) (*Field, error) {
@@ -204,50 +689,397 @@ func findField(ref string, form *Form) (*Field, error) {
 			return f, nil
 		}
 	}
-	return nil, &FormTranslationError{fmt.Sprintf("Could not find field ref %v in form titled %v", ref, form.Title)}
+	return nil, &FormTranslationError{Message: fmt.Sprintf("Could not find field ref %v in form titled %v", ref, form.Title), Reason: ReasonMissingField}
+}
+
+func findFieldByID(id string, form *Form) (*Field, error) {
+	if id != "" {
+		for _, f := range form.Fields {
+			if f.ID == id {
+				return f, nil
+			}
+		}
+	}
+	return nil, &FormTranslationError{Message: fmt.Sprintf("Could not find field id %v in form titled %v", id, form.Title), Reason: ReasonMissingField}
+}
+
+// claimedFields are the destination fields that the fields of form
+// are matched to by an override, ref or ID, so that a match by
+// position doesn't also give them to another field. Matching by
+// shape only leaves them to overrides.
+func claimedFields(form, destForm *Form, opts *TranslatorOptions) map[*Field]bool {
+	claimed := map[*Field]bool{}
+	for _, f := range form.Fields {
+		if ref, ok := opts.FieldOverrides[f.Ref]; ok {
+			if df, err := findField(ref, destForm); err == nil {
+				claimed[df] = true
+			}
+			continue
+		}
+		if opts.Strategy == MatchByShape {
+			continue
+		}
+		if df, err := findField(f.Ref, destForm); err == nil {
+			claimed[df] = true
+		} else if df, err := findFieldByID(f.ID, destForm); err == nil {
+			claimed[df] = true
+		}
+	}
+	return claimed
 }
 
-func prepForms(a, b *Form) {
-	a.Fields = append(a.Fields, a.ThankYouScreens...)
-	b.Fields = append(b.Fields, b.ThankYouScreens...)
+// matchField finds the field in destForm that corresponds to the field
+// at index i of the source form, returning the strategy that matched it.
+func matchField(i int, f *Field, destForm *Form, strategy string, claimed map[*Field]bool) (*Field, string, error) {
+	switch strategy {
+	case MatchByShape:
+		if i >= len(destForm.Fields) {
+			return nil, "", &FormTranslationError{Message: fmt.Sprintf("Could not find field at position %v in form titled %v", i, destForm.Title), Reason: ReasonMissingField}
+		}
+		if df := destForm.Fields[i]; claimed[df] {
+			return nil, "", &FormTranslationError{Message: fmt.Sprintf("Field ref %v is at the position of field ref %v in form titled %v, which an override already matched", f.Ref, df.Ref, destForm.Title), Reason: ReasonShape}
+		}
+		return destForm.Fields[i], MatchByShape, nil
+
+	case MatchByRef:
+		df, err := findField(f.Ref, destForm)
+		return df, MatchByRef, err
+
+	case MatchByID:
+		df, err := findFieldByID(f.ID, destForm)
+		return df, MatchByID, err
+
+	case MatchHybrid:
+		if df, err := findField(f.Ref, destForm); err == nil {
+			return df, MatchByRef, nil
+		}
+		if df, err := findFieldByID(f.ID, destForm); err == nil {
+			return df, MatchByID, nil
+		}
+
+		// NOTE: only trust the position if the field there
+		// is at least of the same type, and no other field
+		// was matched to it by ref or id.
+		if i < len(destForm.Fields) && destForm.Fields[i].Type == f.Type && !claimed[destForm.Fields[i]] {
+			return destForm.Fields[i], MatchByShape, nil
+		}
+		return nil, "", &FormTranslationError{Message: fmt.Sprintf("Could not match field ref %v (id %v) by ref, id or position in form titled %v", f.Ref, f.ID, destForm.Title), Reason: ReasonMissingField}
+	}
+
+	return nil, "", &FormTranslationError{Message: fmt.Sprintf("Unknown field matching strategy: %v", strategy), Reason: ReasonInvalidOptions}
 }
 
-func makeTranslator(form, destForm *Form, byRef bool) (*FormTranslator, error) {
-	prepForms(form, destForm)
+// Sections of a form that are matched separately
+const (
+	SectionFields          = "fields"
+	SectionWelcomeScreens  = "welcome_screens"
+	SectionThankYouScreens = "thankyou_screens"
+)
+
+// NOTE: welcome screens never receive answers, so a form can
+// lack them, or have different ones, and still be translated.
+type formSection struct {
+	name     string
+	form     *Form
+	optional bool
+}
 
-	if !byRef && len(form.Fields) != len(destForm.Fields) {
-		return nil, &FormTranslationError{"Forms have different lengths!"}
+// sections splits a form into views of each section, so that
+// fields are only matched against fields of the same section.
+// NOTE: the views are new Form values and the original form
+// is never modified.
+func sections(form *Form) []*formSection {
+	view := func(fields []*Field) *Form {
+		return &Form{Title: form.Title, Fields: fields}
+	}
+	return []*formSection{
+		{SectionFields, view(form.Fields), false},
+		{SectionWelcomeScreens, view(form.WelcomeScreens), true},
+		{SectionThankYouScreens, view(form.ThankYouScreens), false},
+	}
+}
+
+// TranslatorOptions configure how a FormTranslator is built.
+// FieldOverrides pairs a source ref with a destination ref, and
+// ChoiceOverrides pairs, per source ref, a source response with its
+// destination value. Any field without an override is matched
+// using Strategy, MatchByRef if none is given.
+//
+// With CollectErrors, every field is attempted and the fields
+// that fail are returned together as FormTranslationErrors, along
+// with a partial translator of the fields that succeeded.
+//
+// LabelGrammar names the grammar used to extract lettered options
+// from both forms, unless DestLabelGrammar is given for the
+// destination form. FieldLabelGrammars names the grammar by ref,
+// for fields formatted differently than the rest of their form.
+// LetteredFields sets, by ref, whether a field's choices are labels
+// of options listed in its title, instead of detecting it.
+// AnswerLocation is where those options are listed, see
+// ExtractOptions. Variables resolve placeholders in question text,
+// such as hidden fields, before the options are extracted.
+//
+// Normalization are the steps, such as NormalizeCaseFold, applied
+// to responses both when building the mappings and when translating,
+// see DefaultNormalization. FuzzyMatching lets responses that are
+// close to a choice, but not exactly it, be translated, see MatchChoice.
+type TranslatorOptions struct {
+	Strategy           string
+	FieldOverrides     map[string]string
+	ChoiceOverrides    map[string]map[string]string
+	CollectErrors      bool
+	LabelGrammar       string
+	DestLabelGrammar   string
+	FieldLabelGrammars map[string]string
+	LetteredFields     map[string]bool
+	AnswerLocation     string
+	Variables          map[string]string
+	Normalization      []string
+	FuzzyMatching      bool
+}
+
+func (opts *TranslatorOptions) extractOptions(f *Field, dest bool) (*ExtractOptions, error) {
+	name := opts.LabelGrammar
+	if dest && opts.DestLabelGrammar != "" {
+		name = opts.DestLabelGrammar
+	}
+	if n, ok := opts.FieldLabelGrammars[f.Ref]; ok {
+		name = n
+	}
+
+	grammar, err := GetLabelGrammar(name)
+	if err != nil {
+		return nil, err
+	}
+
+	eo := &ExtractOptions{Grammar: grammar, Location: opts.AnswerLocation, Variables: opts.Variables}
+	if lettered, ok := opts.LetteredFields[f.Ref]; ok {
+		eo.Lettered = &lettered
+	}
+	return eo, nil
+}
+
+// overrideChoices sets the translation of the responses in choices,
+// pairing the overridden choices with the destination choices their
+// values stand for, so that logic and payloads agree with the Mapping.
+func overrideChoices(ft *FieldTranslator, choices map[string]string, src, dst *Field) *FieldTranslator {
+	if ft == nil || !ft.Translate || ft.Kind != KindMapping {
+		ft = &FieldTranslator{Translate: true, Kind: KindMapping, Mapping: map[string]string{}}
+	}
+
+	// the destination choice of each value, as already paired,
+	// or else as labelled in the destination field
+	byValue := map[string]*ChoiceTranslation{}
+	for _, c := range ft.Choices {
+		if v, ok := ft.Mapping[c.Label]; ok {
+			byValue[v] = c
+		}
+	}
+	if dst.Properties != nil {
+		for _, c := range dst.Properties.Choices {
+			if _, ok := byValue[c.Label]; !ok {
+				byValue[c.Label] = &ChoiceTranslation{DestRef: c.Ref, DestLabel: c.Label}
+			}
+		}
+	}
+
+	if len(ft.Choices) == 0 && src.Properties != nil {
+		for _, c := range src.Properties.Choices {
+			ft.Choices = append(ft.Choices, &ChoiceTranslation{Ref: c.Ref, Label: c.Label})
+		}
+	}
+
+	for i, c := range ft.Choices {
+		v, ok := choices[c.Label]
+		if !ok {
+			continue
+		}
+		oc := &ChoiceTranslation{Ref: c.Ref, Label: c.Label, DestLabel: v, MatchedBy: MatchByOverride, Value: c.Value}
+		if d, ok := byValue[v]; ok {
+			oc.DestRef, oc.DestLabel = d.DestRef, d.DestLabel
+		}
+		ft.Choices[i] = oc
+	}
+
+	for k, v := range choices {
+		ft.Mapping[k] = v
+	}
+	return ft
+}
+
+func checkOptions(form *Form, opts *TranslatorOptions) error {
+	switch opts.Strategy {
+	case MatchByShape, MatchByRef, MatchByID, MatchHybrid:
+	default:
+		return &FormTranslationError{Message: fmt.Sprintf("Unknown field matching strategy: %v", opts.Strategy), Reason: ReasonInvalidOptions}
+	}
+
+	refs := []string{}
+	for ref := range opts.FieldOverrides {
+		refs = append(refs, ref)
+	}
+	for ref := range opts.ChoiceOverrides {
+		refs = append(refs, ref)
+	}
+
+	all := &Form{Title: form.Title}
+	for _, section := range sections(form) {
+		all.Fields = append(all.Fields, section.form.Fields...)
+	}
+
+	for _, ref := range refs {
+		if _, err := findField(ref, all); err != nil {
+			return &FormTranslationError{Message: fmt.Sprintf("Override given for field ref %v, which is not in form titled %v", ref, form.Title), Reason: ReasonInvalidOptions}
+		}
+	}
+
+	// NOTE: choice overrides would replace the
+	// translator of any other type of field.
+	for ref := range opts.ChoiceOverrides {
+		f, _ := findField(ref, all)
+		switch f.Type {
+		case "multiple_choice", "dropdown", "picture_choice":
+		default:
+			return &FormTranslationError{Message: fmt.Sprintf("Choice overrides given for field ref %v, which is a %v field without choices", ref, f.Type), Reason: ReasonInvalidOptions}
+		}
+	}
+
+	if _, err := fieldTexts(&Field{}, opts.AnswerLocation, nil); err != nil {
+		return err
+	}
+
+	if err := checkNormalization(opts.Normalization); err != nil {
+		return err
+	}
+
+	grammars := []string{opts.LabelGrammar, opts.DestLabelGrammar}
+	for _, name := range opts.FieldLabelGrammars {
+		grammars = append(grammars, name)
+	}
+	for _, name := range grammars {
+		if _, err := GetLabelGrammar(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func makeMatchedFieldTranslator(i int, f *Field, destForm *Form, opts *TranslatorOptions, claimed map[*Field]bool) (*FieldTranslator, error) {
+	var df *Field
+	var matchedBy string
+	var err error
+
+	if ref, ok := opts.FieldOverrides[f.Ref]; ok {
+		df, err = findField(ref, destForm)
+		matchedBy = MatchByOverride
+	} else {
+		df, matchedBy, err = matchField(i, f, destForm, opts.Strategy, claimed)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ft, err := makeFieldTranslator(f, df, opts)
+
+	// Choice overrides can stand in for a field
+	// whose choices could not be paired
+	if choices, ok := opts.ChoiceOverrides[f.Ref]; ok {
+		ft, err = overrideChoices(ft, choices, f, df), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := normalizeMapping(ft, opts.Normalization); err != nil {
+		return nil, err
+	}
+	ft.Fuzzy = opts.FuzzyMatching && ft.Translate && ft.Kind == KindMapping
+
+	ft.MatchedBy = matchedBy
+	ft.DestRef = df.Ref
+	ft.FieldType = f.Type
+	return ft, nil
+}
+
+func makeTranslator(form, destForm *Form, opts *TranslatorOptions) (*FormTranslator, error) {
+	// NOTE: fields are matched by ref unless told otherwise,
+	// without changing the options of the caller.
+	o := TranslatorOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Strategy == "" {
+		o.Strategy = MatchByRef
+	}
+	opts = &o
+
+	if err := checkOptions(form, opts); err != nil {
+		return nil, err
 	}
 
 	formTranslator := &FormTranslator{Fields: map[string]*FieldTranslator{}}
+	errs := []*FieldTranslationError{}
 
-	for i, f := range form.Fields {
-		var df *Field
-		var err error
+	destSections := sections(destForm)
 
-		if byRef {
-			df, err = findField(f.Ref, destForm)
-			if err != nil {
-				return nil, err
+	for si, section := range sections(form) {
+		src, dst := section.form, destSections[si].form
+
+		// NOTE: overrides are how mismatched shapes get fixed,
+		// so don't insist on the same length when they are given.
+		if opts.Strategy == MatchByShape && len(opts.FieldOverrides) == 0 && !section.optional && len(src.Fields) != len(dst.Fields) {
+			msg := "Forms have different lengths!"
+			if section.name != SectionFields {
+				msg = fmt.Sprintf("Forms have different numbers of %v!", section.name)
 			}
-		} else {
-			df = destForm.Fields[i]
+			return nil, &FormTranslationError{Message: msg, Reason: ReasonShape}
 		}
 
-		ft, err := MakeFieldTranslator(f, df)
-		if err != nil {
-			return nil, err
+		claimed := claimedFields(src, dst, opts)
+
+		for i, f := range src.Fields {
+			ft, err := makeMatchedFieldTranslator(i, f, dst, opts, claimed)
+			if err != nil && section.optional {
+				formTranslator.Fields[f.Ref] = &FieldTranslator{Translate: false, FieldType: f.Type}
+				continue
+			}
+			if err != nil {
+				if !opts.CollectErrors {
+					return nil, err
+				}
+				errs = append(errs, &FieldTranslationError{Ref: f.Ref, Section: section.name, Index: i, Reason: reasonOf(err, ""), Err: err})
+				continue
+			}
+			formTranslator.Fields[f.Ref] = ft
 		}
-		formTranslator.Fields[f.Ref] = ft
+	}
+
+	if len(errs) > 0 {
+		return formTranslator, &FormTranslationErrors{errs}
 	}
 
 	return formTranslator, nil
 }
 
+func MakeTranslator(form, destForm *Form, opts *TranslatorOptions) (*FormTranslator, error) {
+	return makeTranslator(form, destForm, opts)
+}
+
 func MakeTranslatorByShape(form, destForm *Form) (*FormTranslator, error) {
-	return makeTranslator(form, destForm, false)
+	return makeTranslator(form, destForm, &TranslatorOptions{Strategy: MatchByShape})
 }
 
 func MakeTranslatorByRef(form, destForm *Form) (*FormTranslator, error) {
-	return makeTranslator(form, destForm, true)
+	return makeTranslator(form, destForm, &TranslatorOptions{Strategy: MatchByRef})
+}
+
+func MakeTranslatorByID(form, destForm *Form) (*FormTranslator, error) {
+	return makeTranslator(form, destForm, &TranslatorOptions{Strategy: MatchByID})
+}
+
+// MakeTranslatorHybrid matches each field by ref, then by ID,
+// then by position.
+func MakeTranslatorHybrid(form, destForm *Form) (*FormTranslator, error) {
+	return makeTranslator(form, destForm, &TranslatorOptions{Strategy: MatchHybrid})
 }
@@ -134,7 +134,7 @@ func TestExtractAnswersGetsSimpleLabels(t *testing.T) {
 
 	res, err := ExtractAnswers(f)
 	assert.Nil(t, err)
-	assert.Equal(t, []*Answer{{"Male", "Male"}, {"Female", "Female"}, {"Other", "Other"}}, res)
+	assert.Equal(t, []*Answer{{"Male", "Male", AnswerSourceChoices, ""}, {"Female", "Female", AnswerSourceChoices, ""}, {"Other", "Other", AnswerSourceChoices, ""}}, res)
 }
 
 func TestExtractAnswersDoesntFailIfNoAnswers(t *testing.T) {
@@ -153,12 +153,17 @@ func TestExtractAnswersDoesntFailIfNoAnswers(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
-func TestDoesntTranslateCertainFieldTypesLikeOpinionScale(t *testing.T) {
+func TestDoesntTranslateCertainFieldTypesLikeShortText(t *testing.T) {
 	fields := []string{
-		`{"id": "YmJEQUEqh0h1", "properties": {"labels": {"left": "Not at all concerned", "right": "Very concerned"}, "start_at_one": true, "steps": 5},
+		`{"id": "YmJEQUEqh0h1",
                   "ref": "9ddb9864-e684-4c69-8dfe-24648ce5a6a0",
-                  "title": "How concerned are you about getting infected with COVID-19?",
-                  "type": "opinion_scale",
+                  "title": "What is your name?",
+                  "type": "short_text",
+                  "validations": {"required": false}}`,
+		`{"id": "YmJEQUEqh0h2",
+                  "ref": "9ddb9864-e684-4c69-8dfe-24648ce5a6a1",
+                  "title": "Tell us more about how you feel about COVID-19",
+                  "type": "long_text",
                   "validations": {"required": false}}`,
 	}
 
@@ -242,7 +247,7 @@ func TestExtractAnswersGetsFromText(t *testing.T) {
 		json.Unmarshal([]byte(field), f)
 
 		res, _ := ExtractAnswers(f)
-		expected := []*Answer{{"A", "foo 91  bar"}, {"B", "Jharkhand"}, {"C", "Odisha"}, {"D", "Uttar Pradesh"}}
+		expected := []*Answer{{"A", "foo 91  bar", AnswerSourceTitle, ""}, {"B", "Jharkhand", AnswerSourceTitle, ""}, {"C", "Odisha", AnswerSourceTitle, ""}, {"D", "Uttar Pradesh", AnswerSourceTitle, ""}}
 		assert.Equal(t, expected, res)
 	}
 }
@@ -267,7 +272,7 @@ func TestExtractAnswersGetsFromTextWithProblematicStartingLetter(t *testing.T) {
 
 		res, _ := ExtractAnswers(f)
 
-		expected := []*Answer{{"A", "Very difficult"}, {"B", "A bit difficult"}, {"C", "Quite easy"}, {"D", "Very easy"}, {"E", "Don’t know/Can’t say"}}
+		expected := []*Answer{{"A", "Very difficult", AnswerSourceTitle, ""}, {"B", "A bit difficult", AnswerSourceTitle, ""}, {"C", "Quite easy", AnswerSourceTitle, ""}, {"D", "Very easy", AnswerSourceTitle, ""}, {"E", "Don’t know/Can’t say", AnswerSourceTitle, ""}}
 
 		assert.Equal(t, expected, res)
 	}
@@ -467,6 +472,215 @@ func TestMakeMCTranslatorTranslatesLanguagesWithLabels(t *testing.T) {
 	assert.Equal(t, "झारखंड", res)
 }
 
+func TestMakeDropdownTranslatorTranslatesLanguages(t *testing.T) {
+	jsons := []string{
+		`{"id": "vjl6LihKMtcX",
+         "title": "आप किस राज्य में रहते हैं?",
+         "ref": "20218ad0-96c8-4799-bdfe-90c689c5c206",
+         "properties": {"choices": [{"label": "छत्तीसगढ़"},
+                                    {"label": "झारखंड"},
+                                    {"label": "ओडिशा"}]},
+         "type": "dropdown"}`,
+
+		`{"title": "Which state do you live in?",
+          "ref": "20218ad0-96c8-4799-bdfe-90c689c5c206",
+          "properties": {
+              "choices": [{"label": "Chhattisgarh"},
+                          {"label": "Jharkhand"},
+                          {"label": "Odisha"}]},
+          "type": "dropdown"}`}
+
+	fields := []*Field{}
+	for _, j := range jsons {
+		f := new(Field)
+		json.Unmarshal([]byte(j), f)
+		fields = append(fields, f)
+	}
+
+	tr, err := MakeDropdownTranslator(fields[0], fields[1])
+	assert.Nil(t, err)
+	res, ok := tr["झारखंड"]
+	assert.True(t, ok)
+	assert.Equal(t, "Jharkhand", res)
+
+	tr, err = MakeDropdownTranslator(fields[1], fields[0])
+	assert.Nil(t, err)
+	res, ok = tr["Odisha"]
+	assert.True(t, ok)
+	assert.Equal(t, "ओडिशा", res)
+}
+
+func TestMakeDropdownTranslatorTranslatesLanguagesWithLabels(t *testing.T) {
+	jsons := []string{
+		`{"id": "mdUpJMSY8Lct",
+           "title": "वर्तमान में आप किस राज्य में रहते हैं?\n- A. छत्तीसगढ़\n- B. झारखंड\n- C. ओडिशा\n- D. उत्तर प्रदेश",
+           "ref": "e959559b-092a-434f-b67f-dca329fab50a",
+           "properties": {"choices": [{"label": "A"},
+                                      {"label": "B"},
+                                      {"label": "C"},
+                                      {"label": "D"}]},
+           "type": "dropdown"}`,
+
+		`{"title": "Which state do you currently live in?\n- A. Chhattisgarh\n- B. Jharkhand\n- C. Odisha\n- D. Uttar Pradesh",
+           "ref": "20218ad0-96c8-4799-bdfe-90c689c5c206",
+           "properties": {"choices": [{"label": "A"},
+                                      {"label": "B"},
+                                      {"label": "C"},
+                                      {"label": "D"}]},
+           "type": "dropdown"}`}
+
+	fields := []*Field{}
+	for _, j := range jsons {
+		f := new(Field)
+		json.Unmarshal([]byte(j), f)
+		fields = append(fields, f)
+	}
+
+	tr, err := MakeDropdownTranslator(fields[0], fields[1])
+	assert.Nil(t, err)
+	assert.Equal(t, "Uttar Pradesh", tr["D"])
+
+	tr, err = MakeDropdownTranslator(fields[1], fields[0])
+	assert.Nil(t, err)
+	assert.Equal(t, "झारखंड", tr["B"])
+}
+
+func TestMakeFieldTranslatorTranslatesDropdown(t *testing.T) {
+	src := &Field{Type: "dropdown", Ref: "foo", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "पुरुष"}, {Label: "महिला"}}}}
+	dst := &Field{Type: "dropdown", Ref: "foo", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "Male"}, {Label: "Female"}}}}
+
+	ft, err := MakeFieldTranslator(src, dst)
+	assert.Nil(t, err)
+	assert.True(t, ft.Translate)
+	assert.Equal(t, "Female", ft.Mapping["महिला"])
+}
+
+func TestMakeFieldTranslatorCapturesMultipleSelection(t *testing.T) {
+	jsons := []string{
+		`{"title": "आपके पास कौन से जानवर हैं?",
+          "ref": "foo",
+          "properties": {"allow_multiple_selection": true,
+                         "choices": [{"label": "कुत्ता"},
+                                     {"label": "बिल्ली"}]},
+          "type": "multiple_choice"}`,
+
+		`{"title": "Which animals do you have?",
+          "ref": "foo",
+          "properties": {"allow_multiple_selection": true,
+                         "choices": [{"label": "Dog"},
+                                     {"label": "Cat"}]},
+          "type": "multiple_choice"}`}
+
+	fields := []*Field{}
+	for _, j := range jsons {
+		f := new(Field)
+		json.Unmarshal([]byte(j), f)
+		fields = append(fields, f)
+	}
+
+	ft, err := MakeFieldTranslator(fields[0], fields[1])
+	assert.Nil(t, err)
+	assert.True(t, ft.MultipleSelection)
+	assert.Equal(t, "Cat", ft.Mapping["बिल्ली"])
+}
+
+func TestMakePictureChoiceTranslatorPairsByPosition(t *testing.T) {
+	jsons := []string{
+		`{"title": "आप खाना कैसे पकाते हैं?",
+          "ref": "foo",
+          "properties": {"choices": [
+              {"label": "गैस", "attachment": {"type": "image", "href": "https://images.typeform.com/images/gas"}},
+              {"label": "लकड़ी", "attachment": {"type": "image", "href": "https://images.typeform.com/images/wood"}}]},
+          "type": "picture_choice"}`,
+
+		`{"title": "How do you cook?",
+          "ref": "foo",
+          "properties": {"choices": [
+              {"label": "Gas", "attachment": {"type": "image", "href": "https://images.typeform.com/images/gas"}},
+              {"label": "Wood", "attachment": {"type": "image", "href": "https://images.typeform.com/images/wood"}}]},
+          "type": "picture_choice"}`}
+
+	fields := []*Field{}
+	for _, j := range jsons {
+		f := new(Field)
+		json.Unmarshal([]byte(j), f)
+		fields = append(fields, f)
+	}
+
+	assert.Equal(t, "https://images.typeform.com/images/wood", fields[0].Properties.Choices[1].Attachment.Href)
+
+	tr, err := MakePictureChoiceTranslator(fields[0], fields[1])
+	assert.Nil(t, err)
+	assert.Equal(t, "Gas", tr["गैस"])
+	assert.Equal(t, "Wood", tr["लकड़ी"])
+}
+
+func TestMakePictureChoiceTranslatorPairsByRef(t *testing.T) {
+	jsons := []string{
+		`{"title": "आप खाना कैसे पकाते हैं?",
+          "ref": "foo",
+          "properties": {"choices": [
+              {"label": "गैस", "ref": "gas"},
+              {"label": "लकड़ी", "ref": "wood"},
+              {"label": "बिजली", "ref": "electric"}]},
+          "type": "picture_choice"}`,
+
+		`{"title": "How do you cook?",
+          "ref": "foo",
+          "properties": {"choices": [
+              {"label": "Electric", "ref": "electric"},
+              {"label": "Gas", "ref": "gas"},
+              {"label": "Wood", "ref": "wood"}]},
+          "type": "picture_choice"}`}
+
+	fields := []*Field{}
+	for _, j := range jsons {
+		f := new(Field)
+		json.Unmarshal([]byte(j), f)
+		fields = append(fields, f)
+	}
+
+	tr, err := MakePictureChoiceTranslator(fields[0], fields[1])
+	assert.Nil(t, err)
+	assert.Equal(t, "Gas", tr["गैस"])
+	assert.Equal(t, "Wood", tr["लकड़ी"])
+	assert.Equal(t, "Electric", tr["बिजली"])
+
+	ft, err := MakeFieldTranslator(fields[1], fields[0])
+	assert.Nil(t, err)
+	assert.True(t, ft.Translate)
+	assert.Equal(t, "बिजली", ft.Mapping["Electric"])
+}
+
+func TestMakePictureChoiceTranslatorFallsBackToPositionWithUnmatchedRefs(t *testing.T) {
+	src := &Field{Type: "picture_choice", Ref: "foo", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "गैस", Ref: "a"}, {Label: "लकड़ी", Ref: "b"}}}}
+	dst := &Field{Type: "picture_choice", Ref: "foo", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "Gas", Ref: "c"}, {Label: "Wood", Ref: "d"}}}}
+
+	tr, err := MakePictureChoiceTranslator(src, dst)
+	assert.Nil(t, err)
+	assert.Equal(t, "Gas", tr["गैस"])
+	assert.Equal(t, "Wood", tr["लकड़ी"])
+}
+
+func TestMakePictureChoiceTranslatorErrorsOnBadChoices(t *testing.T) {
+	src := &Field{Type: "picture_choice", Ref: "foo", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "गैस"}, {Label: "लकड़ी"}}}}
+	dst := &Field{Type: "picture_choice", Ref: "foo", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "Gas"}}}}
+
+	tr, err := MakePictureChoiceTranslator(src, dst)
+	assert.NotNil(t, err)
+	assert.Nil(t, tr)
+
+	tr, err = MakePictureChoiceTranslator(src, &Field{Type: "picture_choice", Ref: "foo"})
+	assert.NotNil(t, err)
+	assert.Nil(t, tr)
+}
+
 func TestMakeFormTranslatorByShape(t *testing.T) {
 
 	jsons := []string{
@@ -546,9 +760,13 @@ func TestMakeFormTranslatorByShape(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "Uttar Pradesh", ft.Fields["bar"].Mapping["D"])
 	assert.Equal(t, "Male", ft.Fields["foo"].Mapping["पुरुष"])
-	assert.Equal(t, false, ft.Fields["baz"].Translate)
+	assert.Equal(t, true, ft.Fields["baz"].Translate)
+	assert.Equal(t, KindNumber, ft.Fields["baz"].Kind)
 	assert.Equal(t, 0, len(ft.Fields["baz"].Mapping))
 
+	assert.Equal(t, MatchByShape, ft.Fields["foo"].MatchedBy)
+	assert.Equal(t, "eng_foo", ft.Fields["foo"].DestRef)
+
 	assert.Equal(t, false, ft.Fields["default_tys"].Translate)
 	assert.Equal(t, 0, len(ft.Fields["default_tys"].Mapping))
 }
@@ -609,7 +827,8 @@ func TestMakeFormTranslatorByRef(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "Uttar Pradesh", ft.Fields["bar"].Mapping["D"])
 	assert.Equal(t, "Male", ft.Fields["foo"].Mapping["पुरुष"])
-	assert.Equal(t, false, ft.Fields["baz"].Translate)
+	assert.Equal(t, true, ft.Fields["baz"].Translate)
+	assert.Equal(t, KindNumber, ft.Fields["baz"].Kind)
 	assert.Equal(t, 0, len(ft.Fields["baz"].Mapping))
 }
 
@@ -709,3 +928,788 @@ func TestMakeFormTranslatorByRef_ThrowsIfMissingRefInBase(t *testing.T) {
 }
 
 // DEAL WITH default_tys!!!
+
+func TestMakeFieldTranslatorTranslatesLocalizedBooleans(t *testing.T) {
+	for _, typ := range []string{"yes_no", "legal"} {
+		f := &Field{Type: typ, Ref: "foo", Title: "क्या आप सहमत हैं?"}
+		df := &Field{Type: typ, Ref: "foo", Title: "Do you agree?"}
+
+		ft, err := MakeFieldTranslator(f, df)
+		assert.Nil(t, err)
+		assert.True(t, ft.Translate)
+
+		assert.Equal(t, BooleanTrue, ft.Mapping["हाँ"])
+		assert.Equal(t, BooleanTrue, ft.Mapping["Sí"])
+		assert.Equal(t, BooleanTrue, ft.Mapping["نعم"])
+		assert.Equal(t, BooleanTrue, ft.Mapping["yes"])
+		assert.Equal(t, BooleanTrue, ft.Mapping["true"])
+		assert.Equal(t, BooleanFalse, ft.Mapping["नहीं"])
+		assert.Equal(t, BooleanFalse, ft.Mapping["No"])
+		assert.Equal(t, BooleanFalse, ft.Mapping["لا"])
+		assert.Equal(t, BooleanFalse, ft.Mapping["false"])
+
+		_, ok := ft.Mapping["शायद"]
+		assert.False(t, ok)
+	}
+}
+
+func TestMakeBooleanTranslatorUsesPluggableVocabulary(t *testing.T) {
+	BooleanVocabulary["sw"] = &BooleanWords{Yes: []string{"Ndiyo"}, No: []string{"Hapana"}}
+	defer delete(BooleanVocabulary, "sw")
+
+	tr, err := MakeBooleanTranslator(&Field{Type: "yes_no"}, &Field{Type: "yes_no"})
+	assert.Nil(t, err)
+	assert.Equal(t, BooleanTrue, tr["Ndiyo"])
+	assert.Equal(t, BooleanFalse, tr["hapana"])
+}
+
+func TestMakeBooleanTranslatorErrorsOnConflictingVocabulary(t *testing.T) {
+	BooleanVocabulary["xx"] = &BooleanWords{Yes: []string{"No"}}
+	defer delete(BooleanVocabulary, "xx")
+
+	tr, err := MakeBooleanTranslator(&Field{Type: "yes_no"}, &Field{Type: "yes_no"})
+	assert.NotNil(t, err)
+	assert.Nil(t, tr)
+}
+
+func TestMakeFormTranslatorByID(t *testing.T) {
+	jsons := []string{
+		`{"title": "hindi", "fields": [
+          {"id": "vjl6LihKMtcX",
+          "title": "आपका लिंग क्या है? ",
+          "ref": "foo",
+          "properties": {"choices": [{"label": "पुरुष"},
+                                    {"label": "महिला"},
+                                    {"label": "अन्य"}]},
+          "type": "multiple_choice"},
+          {"id": "mdUpJMSY8Lct",
+           "title": "आपकी उम्र क्या है?",
+           "ref": "bar",
+           "properties": {},
+           "type": "number"}]}`,
+
+		`{"title": "english", "fields": [
+          {"id": "mdUpJMSY8Lct",
+           "title": "How old are you?",
+           "ref": "eng_bar",
+           "properties": {},
+           "type": "number"},
+          {"id": "vjl6LihKMtcX",
+           "title": "What is your gender? ",
+           "ref": "eng_foo",
+           "properties": {
+              "choices": [{"label": "Male"},
+                          {"label": "Female"},
+                          {"label": "Other"}]},
+           "type": "multiple_choice"}]}`}
+
+	forms := []Form{}
+	for _, j := range jsons {
+		f := new(Form)
+		json.Unmarshal([]byte(j), f)
+		forms = append(forms, *f)
+	}
+
+	ft, err := MakeTranslatorByID(&forms[0], &forms[1])
+	assert.Nil(t, err)
+	assert.Equal(t, "Male", ft.Fields["foo"].Mapping["पुरुष"])
+	assert.Equal(t, "eng_foo", ft.Fields["foo"].DestRef)
+	assert.Equal(t, MatchByID, ft.Fields["foo"].MatchedBy)
+	assert.Equal(t, "eng_bar", ft.Fields["bar"].DestRef)
+
+	forms[1].Fields[0].ID = "somethingElse"
+	_, err = MakeTranslatorByID(&forms[0], &forms[1])
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "id mdUpJMSY8Lct")
+}
+
+func TestMakeFormTranslatorHybridRecordsStrategy(t *testing.T) {
+	jsons := []string{
+		`{"title": "hindi", "fields": [
+          {"id": "vjl6LihKMtcX",
+          "title": "आपका लिंग क्या है? ",
+          "ref": "foo",
+          "properties": {"choices": [{"label": "पुरुष"},
+                                    {"label": "महिला"},
+                                    {"label": "अन्य"}]},
+          "type": "multiple_choice"},
+          {"id": "mdUpJMSY8Lct",
+           "title": "आपकी उम्र क्या है?",
+           "ref": "bar",
+           "properties": {},
+           "type": "number"},
+          {"id": "aaaaaaaaaaaa",
+           "title": "आपका नाम क्या है?",
+           "ref": "baz",
+           "type": "short_text"}]}`,
+
+		`{"title": "english", "fields": [
+          {"id": "somethingElse",
+           "title": "What is your gender? ",
+           "ref": "foo",
+           "properties": {
+              "choices": [{"label": "Male"},
+                          {"label": "Female"},
+                          {"label": "Other"}]},
+           "type": "multiple_choice"},
+          {"id": "mdUpJMSY8Lct",
+           "title": "How old are you?",
+           "ref": "eng_bar",
+           "properties": {},
+           "type": "number"},
+          {"id": "bbbbbbbbbbbb",
+           "title": "What is your name?",
+           "ref": "eng_baz",
+           "type": "short_text"}]}`}
+
+	forms := []Form{}
+	for _, j := range jsons {
+		f := new(Form)
+		json.Unmarshal([]byte(j), f)
+		forms = append(forms, *f)
+	}
+
+	ft, err := MakeTranslatorHybrid(&forms[0], &forms[1])
+	assert.Nil(t, err)
+	assert.Equal(t, MatchByRef, ft.Fields["foo"].MatchedBy)
+	assert.Equal(t, "Female", ft.Fields["foo"].Mapping["महिला"])
+	assert.Equal(t, MatchByID, ft.Fields["bar"].MatchedBy)
+	assert.Equal(t, "eng_bar", ft.Fields["bar"].DestRef)
+	assert.Equal(t, MatchByShape, ft.Fields["baz"].MatchedBy)
+	assert.Equal(t, "eng_baz", ft.Fields["baz"].DestRef)
+}
+
+func TestMakeFormTranslatorHybridErrorsWhenNothingMatches(t *testing.T) {
+	form := &Form{Title: "hindi", Fields: []*Field{
+		{ID: "a", Ref: "foo", Type: "short_text"},
+		{ID: "b", Ref: "bar", Type: "number"},
+	}}
+	destForm := &Form{Title: "english", Fields: []*Field{
+		{ID: "c", Ref: "eng_foo", Type: "short_text"},
+		{ID: "d", Ref: "eng_bar", Type: "short_text"},
+	}}
+
+	_, err := MakeTranslatorHybrid(form, destForm)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "ref bar")
+}
+
+func TestMakeFormTranslatorHybridDoesntReuseMatchedFields(t *testing.T) {
+	form := &Form{Title: "hindi", Fields: []*Field{
+		{Ref: "a", Type: "short_text"},
+		{Ref: "b", Type: "short_text"},
+	}}
+	destForm := &Form{Title: "english", Fields: []*Field{
+		{Ref: "b", Type: "short_text"},
+		{Ref: "c", Type: "short_text"},
+	}}
+
+	// field a would be matched by position to b, which
+	// is already the destination of field b by ref
+	_, err := MakeTranslatorHybrid(form, destForm)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "ref a")
+
+	ft, err := MakeTranslator(form, destForm, &TranslatorOptions{Strategy: MatchHybrid, CollectErrors: true})
+	assert.NotNil(t, err)
+	assert.Equal(t, "b", ft.Fields["b"].DestRef)
+	assert.Nil(t, ft.Fields["a"])
+}
+
+func TestMakeTranslatorWithFieldOverrides(t *testing.T) {
+	jsons := []string{
+		`{"title": "hindi", "fields": [
+          {"title": "आपका लिंग क्या है? ",
+          "ref": "foo",
+          "properties": {"choices": [{"label": "पुरुष"},
+                                    {"label": "महिला"},
+                                    {"label": "अन्य"}]},
+          "type": "multiple_choice"},
+          {"title": "आपकी उम्र क्या है?",
+           "ref": "bar",
+           "properties": {},
+           "type": "number"}]}`,
+
+		`{"title": "english", "fields": [
+          {"title": "What is your gender? ",
+           "ref": "foo",
+           "properties": {
+              "choices": [{"label": "Male"},
+                          {"label": "Female"},
+                          {"label": "Other"}]},
+           "type": "multiple_choice"},
+          {"title": "Where do you live?",
+           "ref": "inserted",
+           "type": "short_text"},
+          {"title": "How old are you?",
+           "ref": "age",
+           "properties": {},
+           "type": "number"}]}`}
+
+	forms := []Form{}
+	for _, j := range jsons {
+		f := new(Form)
+		json.Unmarshal([]byte(j), f)
+		forms = append(forms, *f)
+	}
+
+	_, err := MakeTranslatorByRef(&forms[0], &forms[1])
+	assert.NotNil(t, err)
+
+	opts := &TranslatorOptions{Strategy: MatchByRef, FieldOverrides: map[string]string{"bar": "age"}}
+	ft, err := MakeTranslator(&forms[0], &forms[1], opts)
+	assert.Nil(t, err)
+	assert.Equal(t, MatchByRef, ft.Fields["foo"].MatchedBy)
+	assert.Equal(t, "Female", ft.Fields["foo"].Mapping["महिला"])
+	assert.Equal(t, MatchByOverride, ft.Fields["bar"].MatchedBy)
+	assert.Equal(t, "age", ft.Fields["bar"].DestRef)
+	assert.Equal(t, KindNumber, ft.Fields["bar"].Kind)
+}
+
+func TestMakeTranslatorWithFieldOverridesAndDifferentShapes(t *testing.T) {
+	form := &Form{Title: "hindi", Fields: []*Field{
+		{Ref: "foo", Type: "short_text"},
+		{Ref: "bar", Type: "number"},
+	}}
+	destForm := &Form{Title: "english", Fields: []*Field{
+		{Ref: "eng_foo", Type: "short_text"},
+		{Ref: "inserted", Type: "short_text"},
+		{Ref: "eng_bar", Type: "number"},
+	}}
+
+	_, err := MakeTranslatorByShape(form, destForm)
+	assert.NotNil(t, err)
+
+	opts := &TranslatorOptions{Strategy: MatchByShape, FieldOverrides: map[string]string{"bar": "eng_bar"}}
+	ft, err := MakeTranslator(form, destForm, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, "eng_foo", ft.Fields["foo"].DestRef)
+	assert.Equal(t, "eng_bar", ft.Fields["bar"].DestRef)
+}
+
+func TestMakeTranslatorByShapeErrorsWhenOverridesTakeAPosition(t *testing.T) {
+	form := &Form{Title: "hindi", Fields: []*Field{{Ref: "q1", Type: "short_text"}, {Ref: "q2", Type: "short_text"}}}
+	destForm := &Form{Title: "english", Fields: []*Field{{Ref: "d1", Type: "short_text"}, {Ref: "d2", Type: "short_text"}}}
+
+	opts := &TranslatorOptions{Strategy: MatchByShape, FieldOverrides: map[string]string{"q1": "d2"}}
+	_, err := MakeTranslator(form, destForm, opts)
+	assert.NotNil(t, err)
+	assert.Equal(t, ReasonShape, reasonOf(err, ""))
+	assert.Contains(t, err.Error(), "ref q2")
+
+	opts.FieldOverrides = map[string]string{"q1": "d2", "q2": "d1"}
+	ft, err := MakeTranslator(form, destForm, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, "d2", ft.Fields["q1"].DestRef)
+	assert.Equal(t, "d1", ft.Fields["q2"].DestRef)
+}
+
+func TestMakeTranslatorWithChoiceOverrides(t *testing.T) {
+	form := &Form{Title: "hindi", Fields: []*Field{
+		{Ref: "foo", Type: "multiple_choice", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "पुरुष"}, {Label: "महिला"}, {Label: "अन्य"}}}},
+		{Ref: "bar", Type: "multiple_choice", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "हाँ"}, {Label: "नहीं"}}}},
+	}}
+	destForm := &Form{Title: "english", Fields: []*Field{
+		{Ref: "foo", Type: "multiple_choice", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "Male"}, {Label: "Female"}}}},
+		{Ref: "bar", Type: "multiple_choice", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "Yes"}, {Label: "No"}}}},
+	}}
+
+	_, err := MakeTranslatorByRef(form, destForm)
+	assert.NotNil(t, err)
+
+	opts := &TranslatorOptions{
+		Strategy: MatchByRef,
+		ChoiceOverrides: map[string]map[string]string{
+			"foo": {"पुरुष": "Male", "महिला": "Female", "अन्य": "Female"},
+			"bar": {"नहीं": "Yes"},
+		},
+	}
+	ft, err := MakeTranslator(form, destForm, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, "Female", ft.Fields["foo"].Mapping["अन्य"])
+	assert.Equal(t, "Male", ft.Fields["foo"].Mapping["पुरुष"])
+	assert.Equal(t, "Yes", ft.Fields["bar"].Mapping["हाँ"])
+	assert.Equal(t, "Yes", ft.Fields["bar"].Mapping["नहीं"])
+}
+
+func TestChoiceOverridesRepairChoicePairs(t *testing.T) {
+	form := &Form{Title: "hindi", Fields: []*Field{
+		{Ref: "foo", Type: "multiple_choice", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "पुरुष", Ref: "s1"}, {Label: "महिला", Ref: "s2"}}}},
+	}}
+	destForm := &Form{Title: "english", Fields: []*Field{
+		{Ref: "foo", Type: "multiple_choice", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "Male", Ref: "d1"}, {Label: "Female", Ref: "d2"}}}},
+	}}
+
+	opts := &TranslatorOptions{
+		Strategy:        MatchByRef,
+		ChoiceOverrides: map[string]map[string]string{"foo": {"पुरुष": "Female", "महिला": "Male"}},
+	}
+	ft, err := MakeTranslator(form, destForm, opts)
+	assert.Nil(t, err)
+
+	res, err := TranslateResponse("foo", "पुरुष", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "Female", *res.Value)
+	assert.Equal(t, &ChoiceTranslation{"s1", "पुरुष", "d2", "Female", MatchByOverride, ""}, res.Match.Choice)
+
+	rules, err := TranslateLogic([]*LogicRule{{Type: "field", Ref: "foo", Actions: []*LogicAction{
+		{Action: "jump", Condition: &LogicVar{Op: "is", Vars: []*LogicVar{
+			{Type: "field", Value: "foo"}, {Type: "choice", Value: "s1"}}}}}}}, ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "d2", rules[0].Actions[0].Condition.Vars[1].Value)
+
+	r, _, err := TranslateFormResponse(&FormResponse{Answers: []*ResponseAnswer{
+		{Type: "choice", Field: &ResponseField{Type: "multiple_choice", Ref: "foo"}, Choice: &ResponseChoice{Label: "पुरुष"}}}}, ft, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, &ResponseChoice{Label: "Female", Ref: "d2"}, r.Answers[0].Choice)
+}
+
+func TestMakeTranslatorErrorsOnOverrideForMissingRef(t *testing.T) {
+	form := &Form{Title: "hindi", Fields: []*Field{{Ref: "foo", Type: "short_text"}}}
+	destForm := &Form{Title: "english", Fields: []*Field{{Ref: "foo", Type: "short_text"}}}
+
+	opts := &TranslatorOptions{Strategy: MatchByRef, FieldOverrides: map[string]string{"bar": "foo"}}
+	_, err := MakeTranslator(form, destForm, opts)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "ref bar")
+
+	opts = &TranslatorOptions{Strategy: MatchByRef, FieldOverrides: map[string]string{"foo": "baz"}}
+	_, err = MakeTranslator(form, destForm, opts)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "ref baz")
+}
+
+func TestMakeTranslatorDefaultsToMatchByRef(t *testing.T) {
+	form := &Form{Title: "hindi", Fields: []*Field{{Ref: "foo", Type: "short_text"}, {Ref: "bar", Type: "number"}}}
+	destForm := &Form{Title: "english", Fields: []*Field{{Ref: "bar", Type: "number"}, {Ref: "foo", Type: "short_text"}}}
+
+	for _, opts := range []*TranslatorOptions{nil, {}, {CollectErrors: true}} {
+		ft, err := MakeTranslator(form, destForm, opts)
+		assert.Nil(t, err)
+		assert.Equal(t, MatchByRef, ft.Fields["foo"].MatchedBy)
+		assert.Equal(t, "bar", ft.Fields["bar"].DestRef)
+	}
+
+	opts := &TranslatorOptions{}
+	_, _ = MakeTranslator(form, destForm, opts)
+	assert.Equal(t, "", opts.Strategy)
+}
+
+func TestMakeTranslatorErrorsOnceOnUnknownStrategy(t *testing.T) {
+	form := &Form{Title: "hindi", Fields: []*Field{{Ref: "foo", Type: "short_text"}, {Ref: "bar", Type: "number"}}}
+
+	ft, err := MakeTranslator(form, form, &TranslatorOptions{Strategy: "title", CollectErrors: true})
+	assert.Nil(t, ft)
+	assert.Equal(t, ReasonInvalidOptions, reasonOf(err, ""))
+	assert.Equal(t, "Unknown field matching strategy: title", err.Error())
+}
+
+func TestMakeTranslatorErrorsOnChoiceOverridesForFieldsWithoutChoices(t *testing.T) {
+	form := &Form{Title: "hindi", Fields: []*Field{
+		{Ref: "n", Type: "opinion_scale", Properties: &FieldProperties{Steps: 5}},
+	}}
+
+	opts := &TranslatorOptions{Strategy: MatchByRef, ChoiceOverrides: map[string]map[string]string{"n": {"x": "y"}}}
+	_, err := MakeTranslator(form, form, opts)
+	assert.NotNil(t, err)
+	assert.Equal(t, ReasonInvalidOptions, reasonOf(err, ""))
+	assert.Contains(t, err.Error(), "ref n")
+}
+
+func TestMakeTranslatorCollectsAllFieldErrors(t *testing.T) {
+	form := &Form{Title: "hindi", Fields: []*Field{
+		{Ref: "foo", Type: "multiple_choice", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "पुरुष"}, {Label: "महिला"}, {Label: "अन्य"}}}},
+		{Ref: "bar", Type: "number"},
+		{Ref: "baz", Type: "multiple_choice", Title: "राज्य?\n- A. झारखंड", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "A"}, {Label: "B"}}}},
+		{Ref: "qux", Type: "short_text"},
+		{Ref: "quux", Type: "multiple_choice", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "हाँ"}, {Label: "नहीं"}}}},
+	}}
+	destForm := &Form{Title: "english", Fields: []*Field{
+		{Ref: "foo", Type: "multiple_choice", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "Male"}, {Label: "Female"}}}},
+		{Ref: "baz", Type: "multiple_choice", Title: "State?\n- A. Jharkhand\n- B. Odisha", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "A"}, {Label: "B"}}}},
+		{Ref: "qux", Type: "short_text"},
+		{Ref: "quux", Type: "multiple_choice", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "Yes"}, {Label: "No"}}}},
+	}}
+
+	ft, err := MakeTranslator(form, destForm, &TranslatorOptions{Strategy: MatchByRef})
+	assert.NotNil(t, err)
+	assert.Nil(t, ft)
+
+	ft, err = MakeTranslator(form, destForm, &TranslatorOptions{Strategy: MatchByRef, CollectErrors: true})
+	assert.NotNil(t, err)
+
+	errs, ok := err.(*FormTranslationErrors)
+	assert.True(t, ok)
+	assert.Equal(t, 3, len(errs.Errors))
+
+	assert.Equal(t, "foo", errs.Errors[0].Ref)
+	assert.Equal(t, 0, errs.Errors[0].Index)
+	assert.Equal(t, ReasonChoiceCount, errs.Errors[0].Reason)
+
+	assert.Equal(t, "bar", errs.Errors[1].Ref)
+	assert.Equal(t, 1, errs.Errors[1].Index)
+	assert.Equal(t, ReasonMissingField, errs.Errors[1].Reason)
+
+	assert.Equal(t, "baz", errs.Errors[2].Ref)
+	assert.Equal(t, 2, errs.Errors[2].Index)
+	assert.Equal(t, ReasonLabelExtraction, errs.Errors[2].Reason)
+
+	assert.Contains(t, err.Error(), "Could not translate 3 fields")
+	assert.Contains(t, err.Error(), "Field bar (fields index 1)")
+
+	assert.Equal(t, 2, len(ft.Fields))
+	assert.Equal(t, false, ft.Fields["qux"].Translate)
+	assert.Equal(t, "Yes", ft.Fields["quux"].Mapping["हाँ"])
+}
+
+func TestMakeMCTranslatorKeepsUnderlyingError(t *testing.T) {
+	src := &Field{Ref: "foo", Type: "multiple_choice", Properties: &FieldProperties{}}
+	dst := &Field{Ref: "foo", Type: "multiple_choice", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "Yes"}, {Label: "No"}}}}
+
+	_, err := MakeMCTranslator(src, dst)
+	assert.NotNil(t, err)
+
+	e, ok := err.(*FormTranslationError)
+	assert.True(t, ok)
+	assert.Equal(t, ReasonNoChoices, e.Reason)
+	assert.NotNil(t, e.Unwrap())
+	assert.Contains(t, e.Unwrap().Error(), "no answer options")
+}
+
+func TestMakeTranslatorDoesntModifyForms(t *testing.T) {
+	jsons := []string{
+		`{"title": "hindi",
+          "welcome_screens": [{"ref": "welcome", "title": "नमस्ते!"}],
+          "fields": [
+          {"title": "आपका लिंग क्या है? ",
+          "ref": "foo",
+          "properties": {"choices": [{"label": "पुरुष"},
+                                    {"label": "महिला"}]},
+          "type": "multiple_choice"}],
+         "thankyou_screens": [{"ref": "default_tys", "title": "धन्यवाद!"}]}`,
+
+		`{"title": "english",
+          "welcome_screens": [{"ref": "eng_welcome", "title": "Hello!"}],
+          "fields": [
+          {"title": "What is your gender? ",
+           "ref": "eng_foo",
+           "properties": {
+              "choices": [{"label": "Male"},
+                          {"label": "Female"}]},
+           "type": "multiple_choice"}],
+         "thankyou_screens": [{"ref": "eng_tys", "title": "Thanks!"}]}`}
+
+	forms := []Form{}
+	for _, j := range jsons {
+		f := new(Form)
+		json.Unmarshal([]byte(j), f)
+		forms = append(forms, *f)
+	}
+
+	for i := 0; i < 3; i++ {
+		ft, err := MakeTranslatorByShape(&forms[0], &forms[1])
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(ft.Fields))
+		assert.Equal(t, "Female", ft.Fields["foo"].Mapping["महिला"])
+		assert.Equal(t, "eng_welcome", ft.Fields["welcome"].DestRef)
+		assert.Equal(t, "eng_tys", ft.Fields["default_tys"].DestRef)
+
+		ft, err = MakeTranslatorHybrid(&forms[0], &forms[1])
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(ft.Fields))
+
+		assert.Equal(t, 1, len(forms[0].Fields))
+		assert.Equal(t, 1, len(forms[1].Fields))
+		assert.Equal(t, 1, len(forms[0].ThankYouScreens))
+		assert.Equal(t, 1, len(forms[1].ThankYouScreens))
+		assert.Equal(t, 1, len(forms[0].WelcomeScreens))
+	}
+}
+
+func TestMakeTranslatorDoesntRequireMatchingWelcomeScreens(t *testing.T) {
+	form := &Form{Title: "hindi",
+		WelcomeScreens:  []*Field{{Ref: "w1"}},
+		Fields:          []*Field{{Ref: "foo", Type: "short_text"}},
+		ThankYouScreens: []*Field{{Ref: "tys"}},
+	}
+	destForm := &Form{Title: "english",
+		WelcomeScreens:  []*Field{{Ref: "w2"}},
+		Fields:          []*Field{{Ref: "foo", Type: "short_text"}},
+		ThankYouScreens: []*Field{{Ref: "tys"}},
+	}
+
+	ft, err := MakeTranslatorByRef(form, destForm)
+	assert.Nil(t, err)
+	assert.False(t, ft.Fields["w1"].Translate)
+	assert.Equal(t, "", ft.Fields["w1"].DestRef)
+	assert.Equal(t, "foo", ft.Fields["foo"].DestRef)
+
+	destForm.WelcomeScreens = nil
+	ft, err = MakeTranslatorByShape(form, destForm)
+	assert.Nil(t, err)
+	assert.False(t, ft.Fields["w1"].Translate)
+	assert.Equal(t, "tys", ft.Fields["tys"].DestRef)
+}
+
+func TestMakeTranslatorByShapeMatchesScreensSeparately(t *testing.T) {
+	form := &Form{Title: "hindi",
+		Fields:          []*Field{{Ref: "foo", Type: "short_text"}},
+		ThankYouScreens: []*Field{{Ref: "tys_a"}, {Ref: "tys_b"}},
+	}
+	destForm := &Form{Title: "english",
+		Fields:          []*Field{{Ref: "eng_foo", Type: "short_text"}, {Ref: "eng_tys", Type: "short_text"}},
+		ThankYouScreens: []*Field{{Ref: "eng_tys_a"}},
+	}
+
+	_, err := MakeTranslatorByShape(form, destForm)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Forms have different lengths!", err.Error())
+
+	destForm.Fields = destForm.Fields[:1]
+	_, err = MakeTranslatorByShape(form, destForm)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "thankyou_screens")
+
+	destForm.ThankYouScreens = append(destForm.ThankYouScreens, &Field{Ref: "eng_tys_b"})
+	ft, err := MakeTranslatorByShape(form, destForm)
+	assert.Nil(t, err)
+	assert.Equal(t, "eng_tys_b", ft.Fields["tys_b"].DestRef)
+}
+
+func TestMakeTranslatorByRefDoesntMatchFieldsToScreens(t *testing.T) {
+	form := &Form{Title: "hindi", Fields: []*Field{{Ref: "foo", Type: "short_text"}}}
+	destForm := &Form{Title: "english", ThankYouScreens: []*Field{{Ref: "foo"}}}
+
+	_, err := MakeTranslatorByRef(form, destForm)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "ref foo")
+}
+
+func TestExtractAnswersFromDescription(t *testing.T) {
+	field := `{"title": "Which state do you currently live in?",
+		"ref": "20218ad0-96c8-4799-bdfe-90c689c5c206",
+		"properties": {
+		"description": "- A. foo 91  bar\n- B. Jharkhand\n- C. Odisha",
+		"choices": [{"label": "A"},
+			{"label": "B"},
+			{"label": "C"}]},
+		"type": "multiple_choice"}`
+
+	f := new(Field)
+	json.Unmarshal([]byte(field), f)
+
+	res, err := ExtractAnswers(f)
+	assert.Nil(t, err)
+	expected := []*Answer{{"A", "foo 91  bar", AnswerSourceDescription, ""}, {"B", "Jharkhand", AnswerSourceDescription, ""}, {"C", "Odisha", AnswerSourceDescription, ""}}
+	assert.Equal(t, expected, res)
+
+	res, err = ExtractAnswersWith(f, &ExtractOptions{Location: AnswerSourceTitle})
+	assert.Nil(t, err)
+	assert.Equal(t, AnswerSourceChoices, res[0].Source)
+
+	_, err = ExtractAnswersWith(f, &ExtractOptions{Location: "nope"})
+	assert.NotNil(t, err)
+}
+
+func TestExtractAnswersPrefersTitleOverDescription(t *testing.T) {
+	f := &Field{Ref: "foo", Type: "multiple_choice",
+		Title: "Which state?\nA. Jharkhand\nB. Odisha",
+		Properties: &FieldProperties{
+			Description: "A. Chhattisgarh\nB. Uttar Pradesh",
+			Choices:     []*FieldChoice{{Label: "A"}, {Label: "B"}}}}
+
+	res, err := ExtractAnswers(f)
+	assert.Nil(t, err)
+	assert.Equal(t, []*Answer{{"A", "Jharkhand", AnswerSourceTitle, ""}, {"B", "Odisha", AnswerSourceTitle, ""}}, res)
+
+	res, err = ExtractAnswersWith(f, &ExtractOptions{Location: AnswerSourceDescription})
+	assert.Nil(t, err)
+	assert.Equal(t, []*Answer{{"A", "Chhattisgarh", AnswerSourceDescription, ""}, {"B", "Uttar Pradesh", AnswerSourceDescription, ""}}, res)
+}
+
+func TestMakeTranslatorWithAnswerLocation(t *testing.T) {
+	form := &Form{Title: "hindi", Fields: []*Field{
+		{Ref: "foo", Type: "multiple_choice", Title: "राज्य?", Properties: &FieldProperties{
+			Description: "A. छत्तीसगढ़\nB. झारखंड",
+			Choices:     []*FieldChoice{{Label: "A"}, {Label: "B"}}}},
+	}}
+	destForm := &Form{Title: "english", Fields: []*Field{
+		{Ref: "foo", Type: "multiple_choice", Title: "State?\nA. Chhattisgarh\nB. Jharkhand", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "A"}, {Label: "B"}}}},
+	}}
+
+	ft, err := MakeTranslatorByRef(destForm, form)
+	assert.Nil(t, err)
+	assert.Equal(t, "झारखंड", ft.Fields["foo"].Mapping["B"])
+
+	// only the destination form lists the options in the title
+	_, err = MakeTranslator(destForm, form, &TranslatorOptions{Strategy: MatchByRef, AnswerLocation: AnswerSourceTitle})
+	assert.NotNil(t, err)
+	assert.Equal(t, ReasonLabelExtraction, reasonOf(err, ""))
+
+	_, err = MakeTranslator(destForm, form, &TranslatorOptions{Strategy: MatchByRef, AnswerLocation: "nope"})
+	assert.NotNil(t, err)
+}
+
+func TestMakeMCTranslatorPairsLetteredOptionsWithFullTextChoices(t *testing.T) {
+	lettered := &Field{Ref: "foo", Type: "multiple_choice", Title: "राज्य?\nA. छत्तीसगढ़\nB. झारखंड", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "A"}, {Label: "B"}}}}
+	fullText := &Field{Ref: "foo", Type: "multiple_choice", Title: "State?", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "Chhattisgarh"}, {Label: "Jharkhand"}}}}
+
+	m, err := MakeMCTranslator(lettered, fullText)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"A": "Chhattisgarh", "B": "Jharkhand"}, m)
+
+	m, err = MakeMCTranslator(fullText, lettered)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"Chhattisgarh": "छत्तीसगढ़", "Jharkhand": "झारखंड"}, m)
+}
+
+func TestExtractAnswersNormalizesQuestionText(t *testing.T) {
+	field := `{"id": "vjS2ZBZSVn0A",
+               "title": "Hi {{hidden:name}}, which *state* do you live in?\n- A. _Jharkhand_\n- B. Near {{field:home}}\n- C. Odisha",
+               "ref": "a8ed5a6e-a3e8-4ab2-a0b9-df6b6088e1f4",
+               "properties": {"choices": [{"label": "A"}, {"label": "B"}, {"label": "C"}]},
+               "type": "multiple_choice"}`
+
+	f := new(Field)
+	json.Unmarshal([]byte(field), f)
+
+	res, err := ExtractAnswers(f)
+	assert.Nil(t, err)
+	assert.Equal(t, []*Answer{
+		{"A", "Jharkhand", AnswerSourceTitle, "- A. _Jharkhand_"},
+		{"B", "Near", AnswerSourceTitle, "- B. Near {{field:home}}"},
+		{"C", "Odisha", AnswerSourceTitle, ""},
+	}, res)
+
+	res, err = ExtractAnswersWith(f, &ExtractOptions{Variables: map[string]string{"field:home": "Bihar"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "Near Bihar", res[1].Value)
+}
+
+func TestMakeMCTranslatorPairsChoicesByRef(t *testing.T) {
+	src := &Field{Type: "multiple_choice", Ref: "foo", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "हाँ", Ref: "yes"}, {Label: "नहीं", Ref: "no"}, {Label: "पता नहीं", Ref: "dk"}}}}
+	dst := &Field{Type: "multiple_choice", Ref: "foo", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "Don't know", Ref: "dk"}, {Label: "Yes", Ref: "yes"}, {Label: "No", Ref: "no"}}}}
+
+	tr, err := MakeMCTranslator(src, dst)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"हाँ": "Yes", "नहीं": "No", "पता नहीं": "Don't know"}, tr)
+
+	ft, err := MakeFieldTranslator(src, dst)
+	assert.Nil(t, err)
+	assert.Equal(t, &ChoiceTranslation{"dk", "पता नहीं", "dk", "Don't know", MatchByRef, ""}, ft.Choices[2])
+}
+
+func TestMakeMCTranslatorPairsChoicesByID(t *testing.T) {
+	src := &Field{Type: "dropdown", Ref: "foo", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "हाँ", ID: "1"}, {Label: "नहीं", ID: "2"}}}}
+	dst := &Field{Type: "dropdown", Ref: "foo", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "No", ID: "2"}, {Label: "Yes", ID: "1"}}}}
+
+	ft, err := MakeFieldTranslator(src, dst)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"हाँ": "Yes", "नहीं": "No"}, ft.Mapping)
+	assert.Equal(t, MatchByID, ft.Choices[0].MatchedBy)
+}
+
+func TestMakeMCTranslatorPairsChoicesByPositionWithoutSharedRefs(t *testing.T) {
+	src := &Field{Type: "multiple_choice", Ref: "foo", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "हाँ", Ref: "a"}, {Label: "नहीं", Ref: "b"}}}}
+	dst := &Field{Type: "multiple_choice", Ref: "foo", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "Yes", Ref: "c"}, {Label: "No", Ref: "d"}}}}
+
+	ft, err := MakeFieldTranslator(src, dst)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"हाँ": "Yes", "नहीं": "No"}, ft.Mapping)
+	assert.Equal(t, MatchByPosition, ft.Choices[1].MatchedBy)
+}
+
+func TestMakeMCTranslatorErrorsOnPartiallySharedChoiceRefs(t *testing.T) {
+	src := &Field{Type: "multiple_choice", Ref: "foo", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "हाँ", Ref: "yes"}, {Label: "नहीं", Ref: "no"}}}}
+	dst := &Field{Type: "multiple_choice", Ref: "foo", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "Yes", Ref: "yes"}, {Label: "No", Ref: "nope"}}}}
+
+	_, err := MakeMCTranslator(src, dst)
+	assert.NotNil(t, err)
+
+	assert.Equal(t, ReasonChoiceMismatch, reasonOf(err, ""))
+
+	src.Type, dst.Type = "picture_choice", "picture_choice"
+	_, err = MakePictureChoiceTranslator(src, dst)
+	assert.NotNil(t, err)
+}
+
+func TestMakeMCTranslatorRefusesPositionalPairingOfRandomizedChoices(t *testing.T) {
+	field := `{"id": "abc", "ref": "foo", "title": "Which do you prefer?",
+               "properties": {"randomize": true, "choices": [{"label": "Tea", "ref": "a"}, {"label": "Coffee", "ref": "b"}]},
+               "type": "multiple_choice"}`
+
+	src := new(Field)
+	json.Unmarshal([]byte(field), src)
+	assert.True(t, src.Properties.Randomize)
+
+	dst := &Field{Type: "multiple_choice", Ref: "foo", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "चाय", Ref: "c"}, {Label: "कॉफ़ी", Ref: "d"}}}}
+
+	_, err := MakeMCTranslator(src, dst)
+	assert.NotNil(t, err)
+	assert.Equal(t, ReasonChoiceOrder, reasonOf(err, ""))
+
+	// pairing by ref doesn't depend on the order
+	dst.Properties.Choices[0].Ref, dst.Properties.Choices[1].Ref = "b", "a"
+	tr, err := MakeMCTranslator(src, dst)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"Tea": "कॉफ़ी", "Coffee": "चाय"}, tr)
+}
+
+func TestMakeTranslatorRefusesPositionalPairingOfSortedChoices(t *testing.T) {
+	src := &Form{Fields: []*Field{{Type: "dropdown", Ref: "foo", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "Ranchi"}, {Label: "Dhanbad"}}}}}}
+	dst := &Form{Fields: []*Field{{Type: "dropdown", Ref: "foo", Properties: &FieldProperties{
+		AlphabeticalOrder: true,
+		Choices:           []*FieldChoice{{Label: "रांची"}, {Label: "धनबाद"}}}}}}
+
+	_, err := MakeTranslatorByRef(src, dst)
+	assert.NotNil(t, err)
+
+	ft, err := MakeTranslator(src, dst, &TranslatorOptions{Strategy: MatchByRef, ChoiceOverrides: map[string]map[string]string{
+		"foo": {"Ranchi": "रांची", "Dhanbad": "धनबाद"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, "धनबाद", ft.Fields["foo"].Mapping["Dhanbad"])
+}
+
+func TestMakeFieldTranslatorRecordsOtherChoice(t *testing.T) {
+	field := `{"id": "abc", "ref": "foo", "title": "How do you cook?",
+               "properties": {"allow_other_choice": true, "choices": [{"label": "Gas"}, {"label": "Wood"}]},
+               "type": "multiple_choice"}`
+
+	f := new(Field)
+	json.Unmarshal([]byte(field), f)
+
+	ft, err := MakeFieldTranslator(f, f)
+	assert.Nil(t, err)
+	assert.True(t, ft.AllowOther)
+
+	f.Properties.AllowOtherChoice = false
+	ft, err = MakeFieldTranslator(f, f)
+	assert.Nil(t, err)
+	assert.False(t, ft.AllowOther)
+}
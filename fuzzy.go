@@ -0,0 +1,200 @@
+package trans
+
+import (
+	"strings"
+)
+
+// Methods by which a response was matched to a choice
+const (
+	MatchExact        = "exact"
+	MatchLabel        = "label"
+	MatchPunctuation  = "label_punctuation"
+	MatchValue        = "value"
+	MatchEditDistance = "edit_distance"
+)
+
+// MinFuzzyConfidence is the confidence below which
+// responses close to a choice are not matched to it.
+var MinFuzzyConfidence = 0.8
+
+// ChoiceMatch is the choice a response was matched to. Response is
+// the key of the choice in the Mapping and Value its translation.
+// Choice is nil for translators that don't pair choices, such as
+// yes_no fields.
+type ChoiceMatch struct {
+	Response   string
+	Value      string
+	Choice     *ChoiceTranslation
+	Method     string
+	Confidence float64
+}
+
+// NOTE: labels with punctuation, such as "B)", are matched by
+// dropping everything but the letters and numbers.
+var labelNormalization = []string{NormalizeNFC, NormalizeZeroWidth, NormalizeCaseFold, NormalizePunctuation, NormalizeWhitespace}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if d := prev[j] + 1; d < cur[j] {
+				cur[j] = d
+			}
+			if d := cur[j-1] + 1; d < cur[j] {
+				cur[j] = d
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// similarity is one minus the edit distance of
+// a and b relative to the longer of the two.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+type choiceCandidate struct {
+	response string
+	choice   *ChoiceTranslation
+	texts    []string
+}
+
+func (ft *FieldTranslator) candidates() []*choiceCandidate {
+	byResponse := map[string]*ChoiceTranslation{}
+	for _, c := range ft.Choices {
+		byResponse[Normalize(c.Label, ft.Normalization)] = c
+	}
+
+	candidates := make([]*choiceCandidate, 0, len(ft.Mapping))
+	for k := range ft.Mapping {
+		c := &choiceCandidate{response: k, choice: byResponse[k], texts: []string{k}}
+		if c.choice != nil && c.choice.Value != "" {
+			c.texts = append(c.texts, c.choice.Value)
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates
+}
+
+// fuzzyMatch matches the response to the candidate it is closest to,
+// trying each method in turn, from the most confident to the least.
+// A response that matches more than one choice equally well is not
+// matched.
+func (ft *FieldTranslator) fuzzyMatch(response string) (*ChoiceMatch, bool) {
+	candidates := ft.candidates()
+
+	methods := []struct {
+		name  string
+		score func(response string, c *choiceCandidate) float64
+	}{
+		{MatchLabel, func(r string, c *choiceCandidate) float64 {
+			if Normalize(r, DefaultNormalization) == Normalize(c.response, DefaultNormalization) {
+				return 0.95
+			}
+			return 0
+		}},
+		{MatchPunctuation, func(r string, c *choiceCandidate) float64 {
+			n := Normalize(r, labelNormalization)
+			if n != "" && n == Normalize(c.response, labelNormalization) {
+				return 0.9
+			}
+			return 0
+		}},
+		{MatchValue, func(r string, c *choiceCandidate) float64 {
+			n := Normalize(r, labelNormalization)
+			for _, t := range c.texts[1:] {
+				if n != "" && n == Normalize(t, labelNormalization) {
+					return 0.9
+				}
+			}
+			return 0
+		}},
+		{MatchEditDistance, func(r string, c *choiceCandidate) float64 {
+			best := 0.0
+			for _, t := range c.texts {
+				if s := similarity(Normalize(r, DefaultNormalization), Normalize(t, DefaultNormalization)); s > best {
+					best = s
+				}
+			}
+			return best
+		}},
+	}
+
+	for _, m := range methods {
+		var best *choiceCandidate
+		score, ambiguous := 0.0, false
+
+		for _, c := range candidates {
+			s := m.score(response, c)
+			switch {
+			case s > score:
+				best, score, ambiguous = c, s, false
+			case s == score && s > 0 && ft.Mapping[c.response] != ft.Mapping[best.response]:
+				ambiguous = true
+			}
+		}
+
+		if best == nil || ambiguous || score < MinFuzzyConfidence {
+			continue
+		}
+		return &ChoiceMatch{best.response, ft.Mapping[best.response], best.choice, m.name, score}, true
+	}
+
+	return nil, false
+}
+
+// MatchChoice matches a response to a choice of the field, whether
+// the respondent typed the label, the label with punctuation, such
+// as "b)", the text of the option or something close to it.
+func MatchChoice(qr, response string, ft *FormTranslator) (*ChoiceMatch, error) {
+	fieldTranslator, err := getFieldTranslator(qr, ft)
+	if err != nil {
+		return nil, err
+	}
+	if !fieldTranslator.Translate || fieldTranslator.Kind != KindMapping {
+		return nil, nil
+	}
+
+	if m, ok := fieldTranslator.matchExact(response); ok {
+		return m, nil
+	}
+	m, _ := fieldTranslator.fuzzyMatch(strings.TrimSpace(response))
+	return m, nil
+}
+
+func (ft *FieldTranslator) matchExact(response string) (*ChoiceMatch, bool) {
+	key := Normalize(response, ft.Normalization)
+	v, ok := ft.Mapping[key]
+	if !ok {
+		return nil, false
+	}
+
+	var choice *ChoiceTranslation
+	for _, c := range ft.Choices {
+		if Normalize(c.Label, ft.Normalization) == key {
+			choice = c
+		}
+	}
+	return &ChoiceMatch{key, v, choice, MatchExact, 1}, true
+}
@@ -0,0 +1,94 @@
+package trans
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getFuzzyTranslator(t *testing.T) *FormTranslator {
+	src := &Form{Fields: []*Field{{Ref: "state", Type: "multiple_choice",
+		Title: "Which state do you live in?\nA. Jharkhand\nB. Odisha\nC. Uttar Pradesh",
+		Properties: &FieldProperties{Choices: []*FieldChoice{
+			{Label: "A"}, {Label: "B"}, {Label: "C"}}}}}}
+
+	dst := &Form{Fields: []*Field{{Ref: "state", Type: "multiple_choice",
+		Title: "आप किस राज्य में रहते हैं?\nA. झारखंड\nB. ओडिशा\nC. उत्तर प्रदेश",
+		Properties: &FieldProperties{Choices: []*FieldChoice{
+			{Label: "A"}, {Label: "B"}, {Label: "C"}}}}}}
+
+	ft, err := MakeTranslator(src, dst, &TranslatorOptions{Strategy: MatchByRef, FuzzyMatching: true})
+	assert.Nil(t, err)
+	return ft
+}
+
+func TestMatchChoice(t *testing.T) {
+	ft := getFuzzyTranslator(t)
+
+	cases := []struct {
+		response string
+		value    string
+		method   string
+	}{
+		{"B", "ओडिशा", MatchExact},
+		{"b", "ओडिशा", MatchLabel},
+		{"B)", "ओडिशा", MatchPunctuation},
+		{" (c) ", "उत्तर प्रदेश", MatchPunctuation},
+		{"Jharkhand", "झारखंड", MatchValue},
+		{"uttar pradesh!", "उत्तर प्रदेश", MatchValue},
+		{"Jharkand", "झारखंड", MatchEditDistance},
+	}
+
+	for _, c := range cases {
+		m, err := MatchChoice("state", c.response, ft)
+		assert.Nil(t, err)
+		assert.Equal(t, c.value, m.Value, c.response)
+		assert.Equal(t, c.method, m.Method, c.response)
+		assert.True(t, m.Confidence >= MinFuzzyConfidence)
+	}
+
+	m, err := MatchChoice("state", "Jharkhand", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "A", m.Response)
+	assert.Equal(t, "A", m.Choice.Label)
+
+	for _, r := range []string{"D", "Bihar", ""} {
+		m, err := MatchChoice("state", r, ft)
+		assert.Nil(t, err)
+		assert.Nil(t, m, r)
+	}
+
+	_, err = MatchChoice("nope", "A", ft)
+	assert.NotNil(t, err)
+}
+
+func TestMatchChoiceRefusesAmbiguousResponses(t *testing.T) {
+	ft := &FormTranslator{map[string]*FieldTranslator{
+		"foo": {Translate: true, Kind: KindMapping, Fuzzy: true, Mapping: map[string]string{
+			"Gaon A": "गांव A",
+			"Gaon B": "गांव B",
+		}},
+	}}
+
+	m, err := MatchChoice("foo", "Gaon C", ft)
+	assert.Nil(t, err)
+	assert.Nil(t, m)
+
+	m, err = MatchChoice("foo", "Gaon  b", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "गांव B", m.Value)
+}
+
+func TestTranslateWithFuzzyMatching(t *testing.T) {
+	ft := getFuzzyTranslator(t)
+
+	res, err := TranslateResponse("state", "b)", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "ओडिशा", *res.Value)
+	assert.Equal(t, MatchPunctuation, res.Match.Method)
+
+	ft.Fields["state"].Fuzzy = false
+	res, err = TranslateResponse("state", "b)", ft)
+	assert.Nil(t, err)
+	assert.Nil(t, res.Value)
+}
@@ -0,0 +1,294 @@
+package trans
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LabelCharacter is the pattern of option labels, substituted for
+// {label} in the pattern of a LabelGrammar: numbers up to 999, roman
+// numerals up to 39, double letters of the same case, or any single
+// letter.
+// NOTE: double letters are limited to one case so that words
+// like "Mr." or "Hi." at the start of a line are not labels.
+const LabelCharacter = `(?:[0-9]{1,3}|[ivx]{2,6}|[IVX]{2,6}|\p{Lu}{2}|\p{Ll}{2}|\p{L})`
+
+// LabelGrammar extracts lettered options, such as "A. foo", from
+// question text. Its pattern must capture the option label in
+// groups named "label" and the option text in a group named "value".
+type LabelGrammar struct {
+	Name    string
+	Pattern string
+	re      *regexp.Regexp
+}
+
+func NewLabelGrammar(name, pattern string) (*LabelGrammar, error) {
+	re, err := regexp.Compile(strings.ReplaceAll(pattern, "{label}", LabelCharacter))
+	if err != nil {
+		return nil, fmt.Errorf("Could not compile label grammar %v: %v", name, err)
+	}
+
+	names := map[string]bool{}
+	for _, n := range re.SubexpNames() {
+		names[n] = true
+	}
+	if !names["label"] || !names["value"] {
+		return nil, fmt.Errorf("Label grammar %v must have groups named label and value: %v", name, pattern)
+	}
+
+	return &LabelGrammar{name, pattern, re}, nil
+}
+
+func mustLabelGrammar(name, pattern string) *LabelGrammar {
+	g, err := NewLabelGrammar(name, pattern)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// bulletLabelCharacter is LabelCharacter without double letters, for
+// "- A foo" bullets, where they could be any two-letter word.
+const bulletLabelCharacter = `(?:[0-9]{1,3}|[ivx]{2,6}|[IVX]{2,6}|\p{L})`
+
+var DefaultLabelGrammar = mustLabelGrammar("default", `(?:^|\n)(?:- ?(?P<label>`+bulletLabelCharacter+`)(?:[^\S\r\n]|[\p{Pd}-\.\)])+|- ?(?P<label>{label})[\p{Pd}-\.\)]+[^\S\r\n]?|(?P<label>{label})[\p{Pd}-\.\)]+[^\S\r\n]?)(?P<value>[^\n]+)`)
+
+// LabelGrammars are the grammars that can be selected by name
+// in TranslatorOptions. Use RegisterLabelGrammar to add more.
+var LabelGrammars = map[string]*LabelGrammar{
+	// A. foo, A) foo, A- foo, - A foo
+	"default": DefaultLabelGrammar,
+
+	// (a) foo
+	"parenthesized": mustLabelGrammar("parenthesized", `(?:^|\n)(?:- ?)?\((?P<label>{label})\)[^\S\r\n]*(?P<value>[^\n]+)`),
+
+	// [1] foo
+	"bracketed": mustLabelGrammar("bracketed", `(?:^|\n)(?:- ?)?\[(?P<label>{label})\][^\S\r\n]*(?P<value>[^\n]+)`),
+
+	// a: foo
+	"colon": mustLabelGrammar("colon", `(?:^|\n)(?:- ?)?(?P<label>{label}):[^\S\r\n]*(?P<value>[^\n]+)`),
+
+	// ① foo
+	"circled": mustLabelGrammar("circled", `(?:^|\n)(?:- ?)?(?P<label>[\x{2460}-\x{2473}\x{24EA}\x{24B6}-\x{24E9}])[^\S\r\n]*(?P<value>[^\n]+)`),
+}
+
+func RegisterLabelGrammar(name, pattern string) error {
+	g, err := NewLabelGrammar(name, pattern)
+	if err != nil {
+		return err
+	}
+	LabelGrammars[name] = g
+	return nil
+}
+
+// GetLabelGrammar returns the grammar registered under name,
+// or the default grammar if no name is given.
+func GetLabelGrammar(name string) (*LabelGrammar, error) {
+	if name == "" {
+		return DefaultLabelGrammar, nil
+	}
+	g, ok := LabelGrammars[name]
+	if !ok {
+		return nil, &FormTranslationError{Message: fmt.Sprintf("Unknown label grammar: %v", name), Reason: ReasonInvalidOptions}
+	}
+	return g, nil
+}
+
+func (g *LabelGrammar) Extract(options string) ([]*Answer, error) {
+	answers, _, err := g.extract(options)
+	return answers, err
+}
+
+// extract also returns the line of options each answer is on.
+func (g *LabelGrammar) extract(options string) ([]*Answer, []int, error) {
+	matches := g.re.FindAllStringSubmatchIndex(options, -1)
+	names := g.re.SubexpNames()
+
+	answers := []*Answer{}
+	lines := []int{}
+
+	for _, match := range matches {
+		label, value, start := "", "", 0
+		for i, name := range names {
+			if match[2*i] < 0 {
+				continue
+			}
+			s := options[match[2*i]:match[2*i+1]]
+			switch {
+			case name == "label" && label == "":
+				label = s
+			case name == "value":
+				value, start = s, match[2*i]
+			}
+		}
+
+		if label == "" {
+			return answers, lines, fmt.Errorf("Could not make labels from options: %s", options)
+		}
+		answers = append(answers, &Answer{Response: label, Value: value})
+		lines = append(lines, strings.Count(options[:start], "\n"))
+	}
+
+	return answers, lines, nil
+}
+
+func ExtractLabels(options string) ([]*Answer, error) {
+	return DefaultLabelGrammar.Extract(options)
+}
+
+type labelSequence struct {
+	name  string
+	label func(i int) string
+}
+
+// letterLabel returns the label at index i in a sequence of letters,
+// continuing after the last letter with double letters: A, B, ...
+// Z, AA, AB, ...
+func letterLabel(i int, first rune) string {
+	if i < 26 {
+		return string(first + rune(i))
+	}
+	return letterLabel(i/26-1, first) + letterLabel(i%26, first)
+}
+
+var romanNumerals = []struct {
+	value   int
+	numeral string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+	{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+	{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+func romanLabel(n int) string {
+	res := ""
+	for _, r := range romanNumerals {
+		for n >= r.value {
+			res += r.numeral
+			n -= r.value
+		}
+	}
+	return res
+}
+
+// alphabetLabel returns a label function for a sequence
+// of letters that ends with the alphabet.
+func alphabetLabel(letters string) func(i int) string {
+	runes := []rune(letters)
+	return func(i int) string {
+		if i >= len(runes) {
+			return ""
+		}
+		return string(runes[i])
+	}
+}
+
+// Native alphabets, in the order used to letter options
+const (
+	devanagariLetters   = "कखगघङचछजझञटठडढणतथदधनपफबभमयरलवशषसह"
+	bengaliLetters      = "কখগঘঙচছজঝঞটঠডঢণতথদধনপফবভমযরলশষসহ"
+	arabicLetters       = "أبتثجحخدذرزسشصضطظعغفقكلمنهوي"
+	arabicAbjadiLetters = "أبجدهوزحطيكلمنسعفصقرشتثخذضظغ"
+)
+
+// circledLabel returns a label function for a sequence of circled
+// characters, such as ① to ⑳, which unicode encodes contiguously.
+func circledLabel(first rune, n int) func(i int) string {
+	return func(i int) string {
+		if i >= n {
+			return ""
+		}
+		return string(first + rune(i))
+	}
+}
+
+var labelSequences = []*labelSequence{
+	{"numeric", func(i int) string { return strconv.Itoa(i + 1) }},
+	{"roman", func(i int) string { return romanLabel(i + 1) }},
+	{"roman_lower", func(i int) string { return strings.ToLower(romanLabel(i + 1)) }},
+	{"latin", func(i int) string { return letterLabel(i, 'A') }},
+	{"latin_lower", func(i int) string { return letterLabel(i, 'a') }},
+	{"devanagari", alphabetLabel(devanagariLetters)},
+	{"bengali", alphabetLabel(bengaliLetters)},
+	{"arabic_abjadi", alphabetLabel(arabicAbjadiLetters)},
+	{"arabic", alphabetLabel(arabicLetters)},
+	{"circled", circledLabel('\u2460', 20)},
+	{"circled_latin", circledLabel('\u24B6', 26)},
+	{"circled_latin_lower", circledLabel('\u24D0', 26)},
+}
+
+// NOTE: the largest numeric label of LabelCharacter
+const maxLabelIndex = 999
+
+// LabelSequence returns the name of the sequence of option labels,
+// such as A, B, C or 1, 2, 3 or i, ii, iii or क, ख, ग, that the
+// labels are the start of, if any.
+func LabelSequence(labels []string) (string, bool) {
+	if len(labels) == 0 {
+		return "", false
+	}
+
+	for _, seq := range labelSequences {
+		matches := true
+		for i, l := range labels {
+			if seq.label(i) != l {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return seq.name, true
+		}
+	}
+	return "", false
+}
+
+func getLabelSequence(name string) (*labelSequence, bool) {
+	for _, seq := range labelSequences {
+		if seq.name == name {
+			return seq, true
+		}
+	}
+	return nil, false
+}
+
+// LabelIndex returns the position of label in the named sequence,
+// so that "क" in devanagari and "C" in latin are both at 0 and 2.
+func LabelIndex(sequence, label string) (int, bool) {
+	seq, ok := getLabelSequence(sequence)
+	if !ok {
+		return 0, false
+	}
+	for i := 0; i < maxLabelIndex; i++ {
+		l := seq.label(i)
+		if l == "" {
+			break
+		}
+		if l == label {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// SequenceLabel returns the label at position i of the named sequence.
+func SequenceLabel(sequence string, i int) (string, bool) {
+	seq, ok := getLabelSequence(sequence)
+	if !ok || i < 0 {
+		return "", false
+	}
+	l := seq.label(i)
+	return l, l != ""
+}
+
+// ConvertLabel returns the label at the same position in the sequence
+// to as label has in the sequence from, such as "ख" to "B".
+func ConvertLabel(label, from, to string) (string, bool) {
+	i, ok := LabelIndex(from, label)
+	if !ok {
+		return "", false
+	}
+	return SequenceLabel(to, i)
+}
@@ -0,0 +1,408 @@
+package trans
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltInLabelGrammars(t *testing.T) {
+	cases := []struct {
+		grammar string
+		text    string
+		labels  []string
+	}{
+		{"default", "Hello\nA. dog walks in\nB) cat walks in", []string{"A", "B"}},
+		{"parenthesized", "Hello\n(a) dog walks in\n(b) cat walks in", []string{"a", "b"}},
+		{"parenthesized", "Hello\n- (A)dog walks in\n- (B) cat walks in", []string{"A", "B"}},
+		{"bracketed", "Hello\n[1] dog walks in\n[2] cat walks in", []string{"1", "2"}},
+		{"colon", "Hello\na: dog walks in\nb: cat walks in", []string{"a", "b"}},
+		{"colon", "Hello\nक: dog walks in\nख: cat walks in", []string{"क", "ख"}},
+		{"circled", "Hello\n① dog walks in\n② cat walks in", []string{"①", "②"}},
+	}
+
+	for _, c := range cases {
+		g, err := GetLabelGrammar(c.grammar)
+		assert.Nil(t, err)
+
+		matches, err := g.Extract(c.text)
+		assert.Nil(t, err)
+		assert.Equal(t, c.labels, mapResponse(matches), c.grammar)
+		assert.Equal(t, "dog walks in", matches[0].Value)
+		assert.Equal(t, "cat walks in", matches[1].Value)
+	}
+}
+
+func TestLabelGrammarsDontMatchOtherStyles(t *testing.T) {
+	text := "Hello paragraph\nA man\nA. dog walks in\nB. cat walks in"
+
+	for _, name := range []string{"parenthesized", "bracketed", "colon", "circled"} {
+		g, _ := GetLabelGrammar(name)
+		matches, err := g.Extract(text)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(matches), name)
+	}
+}
+
+func TestGetLabelGrammar(t *testing.T) {
+	g, err := GetLabelGrammar("")
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultLabelGrammar, g)
+
+	_, err = GetLabelGrammar("nope")
+	assert.NotNil(t, err)
+}
+
+func TestRegisterLabelGrammar(t *testing.T) {
+	err := RegisterLabelGrammar("slash", `(?:^|\n)(?P<label>{label})/[^\S\r\n]*(?P<value>[^\n]+)`)
+	assert.Nil(t, err)
+	defer delete(LabelGrammars, "slash")
+
+	g, err := GetLabelGrammar("slash")
+	assert.Nil(t, err)
+	matches, err := g.Extract("Hello\nA/ dog walks in\nB/ cat walks in")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"A", "B"}, mapResponse(matches))
+
+	err = RegisterLabelGrammar("nogroups", `(?:^|\n)({label})/([^\n]+)`)
+	assert.NotNil(t, err)
+
+	err = RegisterLabelGrammar("broken", `(?P<label>{label}`)
+	assert.NotNil(t, err)
+}
+
+func TestMakeTranslatorWithLabelGrammars(t *testing.T) {
+	form := &Form{Title: "english", Fields: []*Field{
+		{Ref: "foo", Type: "multiple_choice", Title: "State?\nA. Chhattisgarh\nB. Jharkhand", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "A"}, {Label: "B"}}}},
+		{Ref: "bar", Type: "multiple_choice", Title: "Gender?\n[A] Male\n[B] Female", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "A"}, {Label: "B"}}}},
+	}}
+	destForm := &Form{Title: "hindi", Fields: []*Field{
+		{Ref: "foo", Type: "multiple_choice", Title: "राज्य?\n(A) छत्तीसगढ़\n(B) झारखंड", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "A"}, {Label: "B"}}}},
+		{Ref: "bar", Type: "multiple_choice", Title: "लिंग?\n[A] पुरुष\n[B] महिला", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "A"}, {Label: "B"}}}},
+	}}
+
+	_, err := MakeTranslatorByRef(form, destForm)
+	assert.NotNil(t, err)
+	assert.Equal(t, ReasonLabelExtraction, reasonOf(err, ""))
+
+	opts := &TranslatorOptions{
+		Strategy:           MatchByRef,
+		DestLabelGrammar:   "parenthesized",
+		FieldLabelGrammars: map[string]string{"bar": "bracketed"},
+	}
+
+	ft, err := MakeTranslator(form, destForm, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, "झारखंड", ft.Fields["foo"].Mapping["B"])
+	assert.Equal(t, "महिला", ft.Fields["bar"].Mapping["B"])
+}
+
+func TestMakeTranslatorErrorsOnUnknownLabelGrammar(t *testing.T) {
+	form := &Form{Title: "hindi", Fields: []*Field{{Ref: "foo", Type: "short_text"}}}
+
+	_, err := MakeTranslator(form, form, &TranslatorOptions{Strategy: MatchByRef, LabelGrammar: "nope"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "nope")
+
+	_, err = MakeTranslator(form, form, &TranslatorOptions{Strategy: MatchByRef, FieldLabelGrammars: map[string]string{"foo": "nope"}})
+	assert.NotNil(t, err)
+}
+
+func TestExtractLabelsDoesntReadTwoLetterWordsAsBullets(t *testing.T) {
+	matches, err := ExtractLabels("Which?\n- A. foo\n- B. bar\n- no idea here")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"A", "B"}, mapResponse(matches))
+
+	f := &Field{Type: "multiple_choice", Title: "Which?\n- A. foo\n- B. bar\n- no idea here", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "A"}, {Label: "B"}}}}
+	res, err := ExtractAnswers(f)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"foo", "bar"}, []string{res[0].Value, res[1].Value})
+
+	matches, err = ExtractLabels("Which?\n- A foo\n- B bar")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"A", "B"}, mapResponse(matches))
+}
+
+func TestExtractLabelsWithMultiCharacterLabels(t *testing.T) {
+	matches, err := ExtractLabels("Hello\n9. dog walks in\n10. cat walks in\n11) cow walks in")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"9", "10", "11"}, mapResponse(matches))
+	assert.Equal(t, "cat walks in", matches[1].Value)
+
+	matches, err = ExtractLabels("Hello\nZ. dog walks in\nAA. cat walks in\n- AB) cow walks in")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Z", "AA", "AB"}, mapResponse(matches))
+	assert.Equal(t, "cow walks in", matches[2].Value)
+
+	matches, err = ExtractLabels("Hello\ni) dog walks in\nii) cat walks in\niii) cow walks in\niv) pig walks in")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"i", "ii", "iii", "iv"}, mapResponse(matches))
+	assert.Equal(t, "pig walks in", matches[3].Value)
+
+	matches, err = ExtractLabels("Hello\nVIII. dog walks in\nIX. cat walks in\nXIV. cow walks in")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"VIII", "IX", "XIV"}, mapResponse(matches))
+
+	// words are not labels
+	matches, err = ExtractLabels("Hello\nMr. Smith walks in\nHi. cat walks in\nDog. cow walks in")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(matches))
+}
+
+func TestLabelSequence(t *testing.T) {
+	cases := []struct {
+		labels []string
+		name   string
+	}{
+		{[]string{"A", "B", "C"}, "latin"},
+		{[]string{"a", "b"}, "latin_lower"},
+		{[]string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}, "numeric"},
+		{[]string{"I", "II", "III", "IV", "V"}, "roman"},
+		{[]string{"i", "ii", "iii"}, "roman_lower"},
+	}
+
+	for _, c := range cases {
+		name, ok := LabelSequence(c.labels)
+		assert.True(t, ok)
+		assert.Equal(t, c.name, name)
+	}
+
+	letters := []string{}
+	for i := 0; i < 28; i++ {
+		letters = append(letters, letterLabel(i, 'A'))
+	}
+	assert.Equal(t, "Z", letters[25])
+	assert.Equal(t, "AA", letters[26])
+	assert.Equal(t, "AB", letters[27])
+	name, ok := LabelSequence(letters)
+	assert.True(t, ok)
+	assert.Equal(t, "latin", name)
+
+	for _, labels := range [][]string{{"B", "C"}, {"A", "C"}, {"1", "3"}, {"Male", "Female"}, {}} {
+		_, ok := LabelSequence(labels)
+		assert.False(t, ok)
+	}
+}
+
+func TestExtractAnswersWithMoreThanNineOptions(t *testing.T) {
+	title := "Which district do you live in?"
+	choices := []*FieldChoice{}
+	expected := []*Answer{}
+	for i := 1; i <= 12; i++ {
+		label := strconv.Itoa(i)
+		title += fmt.Sprintf("\n%v. District %v", label, i)
+		choices = append(choices, &FieldChoice{Label: label})
+		expected = append(expected, &Answer{label, fmt.Sprintf("District %v", i), AnswerSourceTitle, ""})
+	}
+
+	f := &Field{Ref: "foo", Type: "dropdown", Title: title, Properties: &FieldProperties{Choices: choices}}
+	res, err := ExtractAnswers(f)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, res)
+}
+
+func TestExtractAnswersWithRomanNumerals(t *testing.T) {
+	f := &Field{Ref: "foo", Type: "multiple_choice",
+		Title: "How often?\ni) Never\nii) Sometimes\niii) Often\niv) Always",
+		Properties: &FieldProperties{Choices: []*FieldChoice{
+			{Label: "i"}, {Label: "ii"}, {Label: "iii"}, {Label: "iv"}}}}
+
+	res, err := ExtractAnswers(f)
+	assert.Nil(t, err)
+	assert.Equal(t, []*Answer{{"i", "Never", AnswerSourceTitle, ""}, {"ii", "Sometimes", AnswerSourceTitle, ""}, {"iii", "Often", AnswerSourceTitle, ""}, {"iv", "Always", AnswerSourceTitle, ""}}, res)
+}
+
+func TestExtractAnswersKeepsNumericAnswersWithoutLabelsInTitle(t *testing.T) {
+	f := &Field{Ref: "foo", Type: "multiple_choice",
+		Title: "How many children do you have?",
+		Properties: &FieldProperties{Choices: []*FieldChoice{
+			{Label: "1"}, {Label: "2"}, {Label: "3"}}}}
+
+	res, err := ExtractAnswers(f)
+	assert.Nil(t, err)
+	assert.Equal(t, []*Answer{{"1", "1", AnswerSourceChoices, ""}, {"2", "2", AnswerSourceChoices, ""}, {"3", "3", AnswerSourceChoices, ""}}, res)
+}
+
+func TestExtractAnswersDetectsLetteredChoices(t *testing.T) {
+	cases := []struct {
+		title  string
+		labels []string
+	}{
+		{"How often?\n1. Never\n2. Sometimes\n3. Always", []string{"1", "2", "3"}},
+		{"How often?\na) Never\nb) Sometimes\nc) Always", []string{"a", "b", "c"}},
+		{"How often?\n- A Never\n- B Sometimes\n- C Always", []string{"A", "B", "C"}},
+	}
+
+	for _, c := range cases {
+		choices := []*FieldChoice{}
+		for _, l := range c.labels {
+			choices = append(choices, &FieldChoice{Label: l})
+		}
+		f := &Field{Ref: "foo", Type: "multiple_choice", Title: c.title, Properties: &FieldProperties{Choices: choices}}
+
+		res, err := ExtractAnswers(f)
+		assert.Nil(t, err)
+		assert.Equal(t, c.labels, mapResponse(res))
+		assert.Equal(t, "Never", res[0].Value)
+		assert.Equal(t, "Always", res[2].Value)
+	}
+}
+
+func TestExtractAnswersDoesntMisfireOnLiteralLabels(t *testing.T) {
+	// "A" is a real answer here, not a label
+	f := &Field{Ref: "foo", Type: "multiple_choice",
+		Title: "What is your blood type?\nA. Please choose carefully",
+		Properties: &FieldProperties{Choices: []*FieldChoice{
+			{Label: "A"}, {Label: "AB"}, {Label: "O"}}}}
+
+	res, err := ExtractAnswers(f)
+	assert.Nil(t, err)
+	assert.Equal(t, []*Answer{{"A", "A", AnswerSourceChoices, ""}, {"AB", "AB", AnswerSourceChoices, ""}, {"O", "O", AnswerSourceChoices, ""}}, res)
+
+	// no markers in the title
+	f = &Field{Ref: "foo", Type: "multiple_choice",
+		Title: "Which grade did you get?",
+		Properties: &FieldProperties{Choices: []*FieldChoice{
+			{Label: "A"}, {Label: "B"}, {Label: "C"}}}}
+
+	res, err = ExtractAnswers(f)
+	assert.Nil(t, err)
+	assert.Equal(t, []*Answer{{"A", "A", AnswerSourceChoices, ""}, {"B", "B", AnswerSourceChoices, ""}, {"C", "C", AnswerSourceChoices, ""}}, res)
+}
+
+func TestExtractAnswersWithLetteredOverride(t *testing.T) {
+	yes, no := true, false
+
+	f := &Field{Ref: "foo", Type: "multiple_choice",
+		Title:      "How often?\n1. Never\n2. Sometimes",
+		Properties: &FieldProperties{Choices: []*FieldChoice{{Label: "1"}, {Label: "2"}}}}
+
+	res, err := ExtractAnswersWith(f, &ExtractOptions{Lettered: &no})
+	assert.Nil(t, err)
+	assert.Equal(t, []*Answer{{"1", "1", AnswerSourceChoices, ""}, {"2", "2", AnswerSourceChoices, ""}}, res)
+
+	f.Title = "Which grade did you get?"
+	_, err = ExtractAnswersWith(f, &ExtractOptions{Lettered: &yes})
+	assert.NotNil(t, err)
+}
+
+func TestMakeTranslatorWithLetteredFields(t *testing.T) {
+	form := &Form{Title: "hindi", Fields: []*Field{
+		{Ref: "foo", Type: "multiple_choice", Title: "कितनी बार?\n1. कभी नहीं\n2. कभी-कभी", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "1"}, {Label: "2"}}}},
+	}}
+	destForm := &Form{Title: "english", Fields: []*Field{
+		{Ref: "foo", Type: "multiple_choice", Title: "How often?\n1. Never\n2. Sometimes", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "1"}, {Label: "2"}}}},
+	}}
+
+	ft, err := MakeTranslatorByRef(form, destForm)
+	assert.Nil(t, err)
+	assert.Equal(t, "Sometimes", ft.Fields["foo"].Mapping["2"])
+
+	opts := &TranslatorOptions{Strategy: MatchByRef, LetteredFields: map[string]bool{"foo": false}}
+	ft, err = MakeTranslator(form, destForm, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, "2", ft.Fields["foo"].Mapping["2"])
+}
+
+func TestLabelSequenceWithNativeAlphabets(t *testing.T) {
+	cases := []struct {
+		labels   []string
+		sequence string
+	}{
+		{[]string{"क", "ख", "ग", "घ"}, "devanagari"},
+		{[]string{"ক", "খ", "গ"}, "bengali"},
+		{[]string{"أ", "ب", "ج", "د"}, "arabic_abjadi"},
+		{[]string{"أ", "ب", "ت", "ث"}, "arabic"},
+	}
+
+	for _, c := range cases {
+		seq, ok := LabelSequence(c.labels)
+		assert.True(t, ok)
+		assert.Equal(t, c.sequence, seq)
+	}
+
+	_, ok := LabelSequence([]string{"क", "ग"})
+	assert.False(t, ok)
+}
+
+func TestConvertLabel(t *testing.T) {
+	l, ok := ConvertLabel("ख", "devanagari", "latin")
+	assert.True(t, ok)
+	assert.Equal(t, "B", l)
+
+	l, ok = ConvertLabel("د", "arabic_abjadi", "numeric")
+	assert.True(t, ok)
+	assert.Equal(t, "4", l)
+
+	i, ok := LabelIndex("roman_lower", "iv")
+	assert.True(t, ok)
+	assert.Equal(t, 3, i)
+
+	_, ok = ConvertLabel("ह", "devanagari", "roman")
+	assert.True(t, ok)
+
+	_, ok = ConvertLabel("A", "devanagari", "latin")
+	assert.False(t, ok)
+
+	_, ok = SequenceLabel("bengali", 40)
+	assert.False(t, ok)
+}
+
+func TestMakeTranslatorWithNativeScriptLabels(t *testing.T) {
+	src := &Form{Fields: []*Field{{Ref: "foo", Type: "multiple_choice",
+		Title: "आप कितनी बार जाते हैं?\nक) कभी नहीं\nख) कभी-कभी\nग) हमेशा",
+		Properties: &FieldProperties{Choices: []*FieldChoice{
+			{Label: "क"}, {Label: "ख"}, {Label: "ग"}}}}}}
+
+	dest := &Form{Fields: []*Field{{Ref: "foo", Type: "multiple_choice",
+		Title: "How often do you go?\nA) Never\nB) Sometimes\nC) Always",
+		Properties: &FieldProperties{Choices: []*FieldChoice{
+			{Label: "A"}, {Label: "B"}, {Label: "C"}}}}}}
+
+	ft, err := MakeTranslatorByShape(src, dest)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"क": "Never", "ख": "Sometimes", "ग": "Always"}, ft.Fields["foo"].Mapping)
+}
+
+func TestExtractAnswersWithEachLabelGrammar(t *testing.T) {
+	cases := []struct {
+		grammar string
+		title   string
+		labels  []string
+	}{
+		{"default", "Pet?\nA. dog\nB. cat", []string{"A", "B"}},
+		{"parenthesized", "Pet?\n(a) dog\n(b) cat", []string{"a", "b"}},
+		{"bracketed", "Pet?\n[1] dog\n[2] cat", []string{"1", "2"}},
+		{"colon", "Pet?\ni: dog\nii: cat", []string{"i", "ii"}},
+		{"circled", "Pet?\n① dog\n② cat", []string{"①", "②"}},
+		{"circled", "Pet?\nⒶ dog\nⒷ cat", []string{"Ⓐ", "Ⓑ"}},
+		{"circled", "Pet?\nⓐ dog\nⓑ cat", []string{"ⓐ", "ⓑ"}},
+	}
+
+	for _, c := range cases {
+		g, err := GetLabelGrammar(c.grammar)
+		assert.Nil(t, err)
+
+		choices := []*FieldChoice{}
+		for _, l := range c.labels {
+			choices = append(choices, &FieldChoice{Label: l})
+		}
+		f := &Field{Ref: "foo", Type: "multiple_choice", Title: c.title, Properties: &FieldProperties{Choices: choices}}
+
+		res, err := ExtractAnswersWith(f, &ExtractOptions{Grammar: g})
+		assert.Nil(t, err, c.title)
+		assert.Equal(t, []*Answer{
+			{c.labels[0], "dog", AnswerSourceTitle, ""},
+			{c.labels[1], "cat", AnswerSourceTitle, ""},
+		}, res, c.title)
+	}
+}
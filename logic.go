@@ -0,0 +1,393 @@
+package trans
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// LogicVar is either a value, such as a field, choice, variable
+// or constant, or, when Op is set, a condition over other vars.
+type LogicVar struct {
+	Op    string      `json:"op,omitempty"`
+	Vars  []*LogicVar `json:"vars,omitempty"`
+	Type  string      `json:"type,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type LogicDetails struct {
+	To     *LogicVar `json:"to,omitempty"`
+	Target *LogicVar `json:"target,omitempty"`
+	Value  *LogicVar `json:"value,omitempty"`
+}
+
+type LogicAction struct {
+	Action    string        `json:"action"`
+	Details   *LogicDetails `json:"details,omitempty"`
+	Condition *LogicVar     `json:"condition,omitempty"`
+}
+
+type LogicRule struct {
+	Type    string         `json:"type"`
+	Ref     string         `json:"ref,omitempty"`
+	Actions []*LogicAction `json:"actions"`
+}
+
+func ParseLogic(raw json.RawMessage) ([]*LogicRule, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	rules := []*LogicRule{}
+	err := json.Unmarshal(raw, &rules)
+	if err != nil {
+		return nil, &FormTranslationError{Message: fmt.Sprintf("Could not parse form logic: %v", err), Reason: ReasonLogic, Err: err}
+	}
+	return rules, nil
+}
+
+type logicTranslator struct {
+	ft      *FormTranslator
+	choices map[string]string
+}
+
+func newLogicTranslator(ft *FormTranslator) *logicTranslator {
+	choices := map[string]string{}
+	for _, f := range ft.Fields {
+		for _, c := range f.Choices {
+			if c.Ref != "" {
+				choices[c.Ref] = c.DestRef
+			}
+		}
+	}
+	return &logicTranslator{ft, choices}
+}
+
+func (lt *logicTranslator) ref(ref string) (string, error) {
+	f, ok := lt.ft.Fields[ref]
+	if !ok {
+		return "", &FormTranslationError{Message: fmt.Sprintf("Logic refers to ref %v, which is not in the translator", ref), Reason: ReasonLogic}
+	}
+	return f.destRef(ref), nil
+}
+
+func (lt *logicTranslator) choiceLabel(fieldRef string, label string) string {
+	f, ok := lt.ft.Fields[fieldRef]
+	if !ok {
+		return label
+	}
+	for _, c := range f.Choices {
+		if c.Label == label {
+			return c.DestLabel
+		}
+	}
+	return label
+}
+
+// value translates a single var. The field is the ref of the field
+// it is compared against in a condition, if any, so that constants
+// holding choice labels can be translated.
+func (lt *logicTranslator) value(v *LogicVar, field string) (*LogicVar, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	if v.Op != "" {
+		return lt.condition(v)
+	}
+
+	res := &LogicVar{Type: v.Type, Value: v.Value}
+	s, isString := v.Value.(string)
+
+	switch v.Type {
+	case "field", "thankyou":
+		if !isString {
+			return nil, &FormTranslationError{Message: fmt.Sprintf("Logic has a %v without a ref: %v", v.Type, v.Value), Reason: ReasonLogic}
+		}
+		ref, err := lt.ref(s)
+		if err != nil {
+			return nil, err
+		}
+		res.Value = ref
+
+	case "choice":
+		dest, ok := lt.choices[s]
+		if !isString || !ok || dest == "" {
+			return nil, &FormTranslationError{Message: fmt.Sprintf("Logic refers to choice %v, which is not in the translator", v.Value), Reason: ReasonLogic}
+		}
+		res.Value = dest
+
+	case "constant":
+		if isString && field != "" {
+			res.Value = lt.choiceLabel(field, s)
+		}
+	}
+
+	return res, nil
+}
+
+func (lt *logicTranslator) condition(c *LogicVar) (*LogicVar, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	field := ""
+	for _, v := range c.Vars {
+		if v.Op == "" && v.Type == "field" {
+			field, _ = v.Value.(string)
+		}
+	}
+
+	res := &LogicVar{Op: c.Op, Vars: make([]*LogicVar, len(c.Vars))}
+	for i, v := range c.Vars {
+		tv, err := lt.value(v, field)
+		if err != nil {
+			return nil, err
+		}
+		res.Vars[i] = tv
+	}
+	return res, nil
+}
+
+func (lt *logicTranslator) action(a *LogicAction) (*LogicAction, error) {
+	res := &LogicAction{Action: a.Action}
+
+	cond, err := lt.condition(a.Condition)
+	if err != nil {
+		return nil, err
+	}
+	res.Condition = cond
+
+	if a.Details != nil {
+		d := &LogicDetails{}
+		if d.To, err = lt.value(a.Details.To, ""); err != nil {
+			return nil, err
+		}
+		if d.Target, err = lt.value(a.Details.Target, ""); err != nil {
+			return nil, err
+		}
+		if d.Value, err = lt.value(a.Details.Value, ""); err != nil {
+			return nil, err
+		}
+		res.Details = d
+	}
+
+	return res, nil
+}
+
+func (lt *logicTranslator) rule(r *LogicRule) (*LogicRule, error) {
+	res := &LogicRule{Type: r.Type, Ref: r.Ref, Actions: make([]*LogicAction, len(r.Actions))}
+
+	if r.Type == "field" {
+		ref, err := lt.ref(r.Ref)
+		if err != nil {
+			return nil, err
+		}
+		res.Ref = ref
+	}
+
+	for i, a := range r.Actions {
+		ta, err := lt.action(a)
+		if err != nil {
+			return nil, err
+		}
+		res.Actions[i] = ta
+	}
+	return res, nil
+}
+
+// TranslateLogic produces the logic of the destination form that is
+// equivalent to the given logic of the source form, using the field
+// and choice pairings of the FormTranslator.
+func TranslateLogic(rules []*LogicRule, ft *FormTranslator) ([]*LogicRule, error) {
+	lt := newLogicTranslator(ft)
+
+	res := make([]*LogicRule, len(rules))
+	for i, r := range rules {
+		tr, err := lt.rule(r)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = tr
+	}
+	return res, nil
+}
+
+func TranslateFormLogic(form *Form, ft *FormTranslator) ([]*LogicRule, error) {
+	rules, err := ParseLogic(form.Logic)
+	if err != nil {
+		return nil, err
+	}
+	return TranslateLogic(rules, ft)
+}
+
+// Kinds of divergence between the logic of two forms
+const (
+	DivergenceUntranslatable = "untranslatable"
+	DivergenceMissingRule    = "missing_rule"
+	DivergenceExtraRule      = "extra_rule"
+	DivergenceMissingAction  = "missing_action"
+	DivergenceExtraAction    = "extra_action"
+	DivergenceAction         = "action"
+	DivergenceJump           = "jump_target"
+	DivergenceChoice         = "choice_condition"
+	DivergenceCondition      = "condition"
+)
+
+// LogicDivergence is a place where the logic of the destination form
+// does not do what the logic of the source form does. Ref is the rule's
+// ref in the source form and DestRef in the destination form. Action is
+// the index of the action in the rule, or -1 for the rule as a whole.
+type LogicDivergence struct {
+	Kind    string
+	Ref     string
+	DestRef string
+	Action  int
+	Message string
+}
+
+func ruleKey(typ, ref string) string {
+	return typ + ":" + ref
+}
+
+// diffVars returns the first pair of vars that differ between
+// two conditions, or nils if they are the same.
+func diffVars(a, b *LogicVar) (*LogicVar, *LogicVar) {
+	if a == nil || b == nil {
+		if a == b {
+			return nil, nil
+		}
+		return a, b
+	}
+
+	if a.Op != b.Op || len(a.Vars) != len(b.Vars) {
+		return a, b
+	}
+	if a.Op == "" {
+		if a.Type != b.Type || !reflect.DeepEqual(a.Value, b.Value) {
+			return a, b
+		}
+		return nil, nil
+	}
+
+	for i := range a.Vars {
+		da, db := diffVars(a.Vars[i], b.Vars[i])
+		if da != nil || db != nil {
+			return da, db
+		}
+	}
+	return nil, nil
+}
+
+func describeVar(v *LogicVar) string {
+	if v == nil {
+		return "nothing"
+	}
+	if v.Op != "" {
+		return fmt.Sprintf("condition %v", v.Op)
+	}
+	return fmt.Sprintf("%v %v", v.Type, v.Value)
+}
+
+func compareActions(src *LogicRule, translated, dest *LogicRule) []*LogicDivergence {
+	divergences := []*LogicDivergence{}
+	diverge := func(kind string, i int, msg string, args ...interface{}) {
+		divergences = append(divergences, &LogicDivergence{kind, src.Ref, dest.Ref, i, fmt.Sprintf(msg, args...)})
+	}
+
+	for i, a := range translated.Actions {
+		if i >= len(dest.Actions) {
+			diverge(DivergenceMissingAction, i, "Action %v (%v) of rule %v is missing in the destination form", i, a.Action, src.Ref)
+			continue
+		}
+		d := dest.Actions[i]
+
+		if a.Action != d.Action {
+			diverge(DivergenceAction, i, "Action %v of rule %v is %v, but %v in the destination form", i, src.Ref, a.Action, d.Action)
+			continue
+		}
+
+		ca, cd := diffVars(a.Condition, d.Condition)
+		if ca != nil || cd != nil {
+			kind := DivergenceCondition
+			if (ca != nil && ca.Type == "choice") || (cd != nil && cd.Type == "choice") {
+				kind = DivergenceChoice
+			}
+			diverge(kind, i, "Condition of action %v of rule %v expects %v, but the destination form has %v", i, src.Ref, describeVar(ca), describeVar(cd))
+		}
+
+		var details, destDetails LogicDetails
+		if a.Details != nil {
+			details = *a.Details
+		}
+		if d.Details != nil {
+			destDetails = *d.Details
+		}
+
+		if ta, td := diffVars(details.To, destDetails.To); ta != nil || td != nil {
+			diverge(DivergenceJump, i, "Action %v of rule %v jumps to %v, but the destination form jumps to %v", i, src.Ref, describeVar(ta), describeVar(td))
+		}
+
+		ta, td := diffVars(details.Target, destDetails.Target)
+		if ta == nil && td == nil {
+			ta, td = diffVars(details.Value, destDetails.Value)
+		}
+		if ta != nil || td != nil {
+			diverge(DivergenceAction, i, "Action %v (%v) of rule %v uses %v, but the destination form uses %v", i, a.Action, src.Ref, describeVar(ta), describeVar(td))
+		}
+	}
+
+	for i := len(translated.Actions); i < len(dest.Actions); i++ {
+		diverge(DivergenceExtraAction, i, "Action %v (%v) of rule %v in the destination form is not in the source form", i, dest.Actions[i].Action, dest.Ref)
+	}
+
+	return divergences
+}
+
+// CheckLogic reports every place where the logic of destForm diverges
+// from the logic of form, once translated with the FormTranslator.
+func CheckLogic(form, destForm *Form, ft *FormTranslator) ([]*LogicDivergence, error) {
+	rules, err := ParseLogic(form.Logic)
+	if err != nil {
+		return nil, err
+	}
+	destRules, err := ParseLogic(destForm.Logic)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := map[string]*LogicRule{}
+	for _, r := range destRules {
+		byKey[ruleKey(r.Type, r.Ref)] = r
+	}
+
+	lt := newLogicTranslator(ft)
+	divergences := []*LogicDivergence{}
+	matched := map[string]bool{}
+
+	for _, r := range rules {
+		translated, err := lt.rule(r)
+		if err != nil {
+			divergences = append(divergences, &LogicDivergence{DivergenceUntranslatable, r.Ref, "", -1, err.Error()})
+			continue
+		}
+
+		key := ruleKey(translated.Type, translated.Ref)
+		dest, ok := byKey[key]
+		if !ok {
+			divergences = append(divergences, &LogicDivergence{DivergenceMissingRule, r.Ref, translated.Ref, -1, fmt.Sprintf("Rule for %v %v has no rule for %v in the destination form", r.Type, r.Ref, translated.Ref)})
+			continue
+		}
+		matched[key] = true
+
+		divergences = append(divergences, compareActions(r, translated, dest)...)
+	}
+
+	for _, r := range destRules {
+		if !matched[ruleKey(r.Type, r.Ref)] {
+			divergences = append(divergences, &LogicDivergence{DivergenceExtraRule, "", r.Ref, -1, fmt.Sprintf("Rule for %v %v in the destination form is not in the source form", r.Type, r.Ref)})
+		}
+	}
+
+	return divergences, nil
+}
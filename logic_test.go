@@ -0,0 +1,289 @@
+package trans
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var logicForms = []string{
+	`{"title": "hindi",
+      "fields": [
+        {"id": "vjl6LihKMtcX",
+         "title": "आपका लिंग क्या है? ",
+         "ref": "foo",
+         "properties": {"choices": [{"label": "पुरुष", "ref": "foo_male"},
+                                    {"label": "महिला", "ref": "foo_female"},
+                                    {"label": "अन्य", "ref": "foo_other"}]},
+         "type": "multiple_choice"},
+        {"id": "mdUpJMSY8Lct",
+         "title": "वर्तमान में आप किस राज्य में रहते हैं?\n- A. छत्तीसगढ़\n- B. झारखंड",
+         "ref": "bar",
+         "properties": {"choices": [{"label": "A", "ref": "bar_a"},
+                                    {"label": "B", "ref": "bar_b"}]},
+         "type": "multiple_choice"},
+        {"id": "aaaaaaaaaaaa",
+         "title": "आपकी उम्र क्या है?",
+         "ref": "baz",
+         "properties": {},
+         "type": "number"}],
+      "thankyou_screens": [{"ref": "done", "title": "धन्यवाद!"}],
+      "logic": [
+        {"type": "field",
+         "ref": "foo",
+         "actions": [
+           {"action": "jump",
+            "details": {"to": {"type": "field", "value": "baz"}},
+            "condition": {"op": "is", "vars": [{"type": "field", "value": "foo"},
+                                               {"type": "choice", "value": "foo_female"}]}},
+           {"action": "add",
+            "details": {"target": {"type": "variable", "value": "score"},
+                        "value": {"type": "constant", "value": 2}},
+            "condition": {"op": "always", "vars": []}}]},
+        {"type": "field",
+         "ref": "bar",
+         "actions": [
+           {"action": "jump",
+            "details": {"to": {"type": "thankyou", "value": "done"}},
+            "condition": {"op": "or", "vars": [
+              {"op": "is", "vars": [{"type": "field", "value": "bar"},
+                                    {"type": "constant", "value": "B"}]},
+              {"op": "equal", "vars": [{"type": "hidden", "value": "district"},
+                                       {"type": "constant", "value": "ranchi"}]}]}}]}]}`,
+
+	`{"title": "english",
+      "fields": [
+        {"id": "vjl6LihKMtcX",
+         "title": "What is your gender? ",
+         "ref": "eng_foo",
+         "properties": {"choices": [{"label": "Male", "ref": "eng_foo_male"},
+                                    {"label": "Female", "ref": "eng_foo_female"},
+                                    {"label": "Other", "ref": "eng_foo_other"}]},
+         "type": "multiple_choice"},
+        {"id": "mdUpJMSY8Lct",
+         "title": "Which state do you currently live in?\n- A. Chhattisgarh\n- B. Jharkhand",
+         "ref": "eng_bar",
+         "properties": {"choices": [{"label": "A", "ref": "eng_bar_a"},
+                                    {"label": "B", "ref": "eng_bar_b"}]},
+         "type": "multiple_choice"},
+        {"id": "aaaaaaaaaaaa",
+         "title": "How old are you?",
+         "ref": "eng_baz",
+         "properties": {},
+         "type": "number"}],
+      "thankyou_screens": [{"ref": "eng_done", "title": "Thanks!"}],
+      "logic": [
+        {"type": "field",
+         "ref": "eng_foo",
+         "actions": [
+           {"action": "jump",
+            "details": {"to": {"type": "field", "value": "eng_baz"}},
+            "condition": {"op": "is", "vars": [{"type": "field", "value": "eng_foo"},
+                                               {"type": "choice", "value": "eng_foo_female"}]}},
+           {"action": "add",
+            "details": {"target": {"type": "variable", "value": "score"},
+                        "value": {"type": "constant", "value": 2}},
+            "condition": {"op": "always", "vars": []}}]},
+        {"type": "field",
+         "ref": "eng_bar",
+         "actions": [
+           {"action": "jump",
+            "details": {"to": {"type": "thankyou", "value": "eng_done"}},
+            "condition": {"op": "or", "vars": [
+              {"op": "is", "vars": [{"type": "field", "value": "eng_bar"},
+                                    {"type": "constant", "value": "B"}]},
+              {"op": "equal", "vars": [{"type": "hidden", "value": "district"},
+                                       {"type": "constant", "value": "ranchi"}]}]}}]}]}`,
+}
+
+func getLogicForms() []*Form {
+	forms := []*Form{}
+	for _, j := range logicForms {
+		f := new(Form)
+		json.Unmarshal([]byte(j), f)
+		forms = append(forms, f)
+	}
+	return forms
+}
+
+func TestParseLogic(t *testing.T) {
+	forms := getLogicForms()
+
+	rules, err := ParseLogic(forms[0].Logic)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(rules))
+	assert.Equal(t, "foo", rules[0].Ref)
+	assert.Equal(t, "jump", rules[0].Actions[0].Action)
+	assert.Equal(t, "baz", rules[0].Actions[0].Details.To.Value)
+	assert.Equal(t, "is", rules[0].Actions[0].Condition.Op)
+	assert.Equal(t, "foo_female", rules[0].Actions[0].Condition.Vars[1].Value)
+	assert.Equal(t, "score", rules[0].Actions[1].Details.Target.Value)
+	assert.Equal(t, float64(2), rules[0].Actions[1].Details.Value.Value)
+	assert.Equal(t, "or", rules[1].Actions[0].Condition.Op)
+	assert.Equal(t, "B", rules[1].Actions[0].Condition.Vars[0].Vars[1].Value)
+
+	rules, err = ParseLogic(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, rules)
+
+	_, err = ParseLogic(json.RawMessage(`{"foo": "bar"}`))
+	assert.NotNil(t, err)
+}
+
+func TestTranslateFormLogic(t *testing.T) {
+	forms := getLogicForms()
+
+	ft, err := MakeTranslatorByShape(forms[0], forms[1])
+	assert.Nil(t, err)
+
+	rules, err := TranslateFormLogic(forms[0], ft)
+	assert.Nil(t, err)
+
+	expected, err := ParseLogic(forms[1].Logic)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, rules)
+}
+
+func TestTranslateLogicTranslatesChoiceLabelConstants(t *testing.T) {
+	ft := &FormTranslator{map[string]*FieldTranslator{
+		"foo": {Translate: true, Kind: KindMapping, DestRef: "eng_foo",
+			Mapping: map[string]string{"पुरुष": "Male", "महिला": "Female"},
+			Choices: []*ChoiceTranslation{
+				{Label: "पुरुष", DestLabel: "Male"},
+				{Label: "महिला", DestLabel: "Female"},
+			}},
+	}}
+
+	rules := []*LogicRule{{Type: "field", Ref: "foo", Actions: []*LogicAction{{
+		Action:    "jump",
+		Details:   &LogicDetails{To: &LogicVar{Type: "field", Value: "foo"}},
+		Condition: &LogicVar{Op: "is", Vars: []*LogicVar{{Type: "field", Value: "foo"}, {Type: "constant", Value: "महिला"}}},
+	}}}}
+
+	res, err := TranslateLogic(rules, ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "eng_foo", res[0].Ref)
+	assert.Equal(t, "Female", res[0].Actions[0].Condition.Vars[1].Value)
+
+	// the source logic is left alone
+	assert.Equal(t, "महिला", rules[0].Actions[0].Condition.Vars[1].Value)
+}
+
+func TestTranslateLogicErrorsOnUnknownRefs(t *testing.T) {
+	ft := &FormTranslator{map[string]*FieldTranslator{
+		"foo": {Translate: false, DestRef: "eng_foo"},
+	}}
+
+	rules := []*LogicRule{{Type: "field", Ref: "bar", Actions: []*LogicAction{}}}
+	_, err := TranslateLogic(rules, ft)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "ref bar")
+
+	rules = []*LogicRule{{Type: "field", Ref: "foo", Actions: []*LogicAction{{
+		Action:    "jump",
+		Details:   &LogicDetails{To: &LogicVar{Type: "field", Value: "foo"}},
+		Condition: &LogicVar{Op: "is", Vars: []*LogicVar{{Type: "field", Value: "foo"}, {Type: "choice", Value: "nope"}}},
+	}}}}
+	_, err = TranslateLogic(rules, ft)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "choice nope")
+}
+
+func TestCheckLogicFindsNoDivergenceInEquivalentForms(t *testing.T) {
+	forms := getLogicForms()
+
+	ft, err := MakeTranslatorByShape(forms[0], forms[1])
+	assert.Nil(t, err)
+
+	divergences, err := CheckLogic(forms[0], forms[1], ft)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(divergences))
+}
+
+func setDestLogic(t *testing.T, form *Form, rules []*LogicRule) {
+	b, err := json.Marshal(rules)
+	assert.Nil(t, err)
+	form.Logic = b
+}
+
+func TestCheckLogicReportsDivergences(t *testing.T) {
+	forms := getLogicForms()
+
+	ft, err := MakeTranslatorByShape(forms[0], forms[1])
+	assert.Nil(t, err)
+
+	rules, _ := ParseLogic(forms[1].Logic)
+
+	// jump to the wrong place
+	rules[0].Actions[0].Details.To.Value = "eng_bar"
+	// condition on a choice that maps elsewhere
+	rules[0].Actions[0].Condition.Vars[1].Value = "eng_foo_other"
+	// different calculation
+	rules[0].Actions[1].Details.Value.Value = 3
+	// an extra action
+	rules[0].Actions = append(rules[0].Actions, &LogicAction{Action: "jump",
+		Details:   &LogicDetails{To: &LogicVar{Type: "field", Value: "eng_bar"}},
+		Condition: &LogicVar{Op: "always", Vars: []*LogicVar{}}})
+	// a missing rule, and an extra one
+	rules[1].Ref = "eng_baz"
+
+	setDestLogic(t, forms[1], rules)
+
+	divergences, err := CheckLogic(forms[0], forms[1], ft)
+	assert.Nil(t, err)
+
+	kinds := []string{}
+	for _, d := range divergences {
+		kinds = append(kinds, d.Kind)
+	}
+	assert.Equal(t, []string{
+		DivergenceChoice,
+		DivergenceJump,
+		DivergenceAction,
+		DivergenceExtraAction,
+		DivergenceMissingRule,
+		DivergenceExtraRule,
+	}, kinds)
+
+	assert.Equal(t, "foo", divergences[0].Ref)
+	assert.Equal(t, "eng_foo", divergences[0].DestRef)
+	assert.Equal(t, 0, divergences[0].Action)
+	assert.Contains(t, divergences[0].Message, "eng_foo_female")
+	assert.Contains(t, divergences[0].Message, "eng_foo_other")
+
+	assert.Contains(t, divergences[1].Message, "eng_baz")
+	assert.Contains(t, divergences[1].Message, "eng_bar")
+
+	assert.Equal(t, 1, divergences[2].Action)
+	assert.Equal(t, 2, divergences[3].Action)
+
+	assert.Equal(t, "bar", divergences[4].Ref)
+	assert.Equal(t, "eng_bar", divergences[4].DestRef)
+	assert.Equal(t, -1, divergences[4].Action)
+
+	assert.Equal(t, "eng_baz", divergences[5].DestRef)
+}
+
+func TestCheckLogicReportsMissingActionsAndUntranslatableRules(t *testing.T) {
+	forms := getLogicForms()
+
+	ft, err := MakeTranslatorByShape(forms[0], forms[1])
+	assert.Nil(t, err)
+	delete(ft.Fields, "bar")
+
+	rules, _ := ParseLogic(forms[1].Logic)
+	rules[0].Actions = rules[0].Actions[:1]
+	setDestLogic(t, forms[1], rules)
+
+	divergences, err := CheckLogic(forms[0], forms[1], ft)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(divergences))
+
+	assert.Equal(t, DivergenceMissingAction, divergences[0].Kind)
+	assert.Equal(t, 1, divergences[0].Action)
+	assert.Equal(t, DivergenceUntranslatable, divergences[1].Kind)
+	assert.Equal(t, "bar", divergences[1].Ref)
+	assert.Equal(t, DivergenceExtraRule, divergences[2].Kind)
+	assert.Equal(t, "eng_bar", divergences[2].DestRef)
+}
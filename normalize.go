@@ -0,0 +1,108 @@
+package trans
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Steps of the normalization applied to responses before they are
+// looked up in a FieldTranslator's Mapping
+const (
+	NormalizeNFC         = "nfc"
+	NormalizeZeroWidth   = "zero_width"
+	NormalizeWhitespace  = "whitespace"
+	NormalizeCaseFold    = "casefold"
+	NormalizePunctuation = "punctuation"
+)
+
+// DefaultNormalization makes lookups insensitive to the differences
+// in responses that respondents don't see.
+var DefaultNormalization = []string{NormalizeNFC, NormalizeZeroWidth, NormalizeWhitespace, NormalizeCaseFold}
+
+func isZeroWidth(r rune) bool {
+	switch r {
+	case '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff':
+		return true
+	}
+	return false
+}
+
+var normalizers = map[string]func(string) string{
+	NormalizeNFC: norm.NFC.String,
+	NormalizeZeroWidth: func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if isZeroWidth(r) {
+				return -1
+			}
+			return r
+		}, s)
+	},
+	NormalizeWhitespace: func(s string) string {
+		return strings.Join(strings.Fields(s), " ")
+	},
+	NormalizeCaseFold: func(s string) string {
+		// NOTE: a Caser is stateful, so can't be shared
+		return cases.Fold().String(s)
+	},
+	NormalizePunctuation: func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if unicode.IsPunct(r) {
+				return -1
+			}
+			return r
+		}, s)
+	},
+}
+
+func checkNormalization(steps []string) error {
+	for _, step := range steps {
+		if _, ok := normalizers[step]; !ok {
+			return &FormTranslationError{Message: fmt.Sprintf("Unknown normalization: %v", step), Reason: ReasonInvalidOptions}
+		}
+	}
+	return nil
+}
+
+// Normalize applies the steps of normalization to s, in order.
+// Unknown steps are ignored.
+func Normalize(s string, steps []string) string {
+	for _, step := range steps {
+		if fn, ok := normalizers[step]; ok {
+			s = fn(s)
+		}
+	}
+	return s
+}
+
+// normalizeMapping normalizes the responses of the mapping with
+// the steps of the translator, so that responses normalized the
+// same way can be looked up.
+func normalizeMapping(ft *FieldTranslator, steps []string) error {
+	if len(steps) == 0 || ft.Kind != KindMapping || !ft.Translate {
+		return nil
+	}
+
+	m := make(map[string]string, len(ft.Mapping))
+	for k, v := range ft.Mapping {
+		nk := Normalize(k, steps)
+		if prev, ok := m[nk]; ok && prev != v {
+			return &FormTranslationError{Message: fmt.Sprintf("Responses normalize to the same response %v but translate differently: %v and %v", nk, prev, v), Reason: ReasonNormalization}
+		}
+		m[nk] = v
+	}
+
+	ft.Mapping = m
+	ft.Normalization = steps
+	return nil
+}
+
+// lookup finds the translation of the response in the Mapping,
+// normalizing it the same way the Mapping was.
+func (ft *FieldTranslator) lookup(response string) (string, bool) {
+	t, ok := ft.Mapping[Normalize(response, ft.Normalization)]
+	return t, ok
+}
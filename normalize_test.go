@@ -0,0 +1,68 @@
+package trans
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		s        string
+		steps    []string
+		expected string
+	}{
+		{"Female ", []string{NormalizeWhitespace}, "Female"},
+		{" Don't  know ", []string{NormalizeWhitespace}, "Don't know"},
+		{"FEMALE", []string{NormalizeCaseFold}, "female"},
+		{"Straße", []string{NormalizeCaseFold}, "strasse"},
+		{"ma\u0301s", []string{NormalizeNFC}, "m\u00e1s"},
+		{"\u0928\u093c", []string{NormalizeNFC}, "\u0929"},
+		{"हाँ\u200d", []string{NormalizeZeroWidth}, "हाँ"},
+		{"Yes!", []string{NormalizePunctuation}, "Yes"},
+		{"हाँ।", []string{NormalizePunctuation}, "हाँ"},
+		{"Female ", nil, "Female "},
+		{" \u200bMa\u0301s ", DefaultNormalization, "m\u00e1s"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, Normalize(c.s, c.steps))
+	}
+}
+
+func TestTranslateNormalizesResponses(t *testing.T) {
+	src := &Form{Fields: []*Field{{Ref: "foo", Type: "multiple_choice", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "Female"}, {Label: "Male"}}}}}}
+	dst := &Form{Fields: []*Field{{Ref: "foo", Type: "multiple_choice", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "महिला"}, {Label: "पुरुष"}}}}}}
+
+	ft, err := MakeTranslator(src, dst, &TranslatorOptions{Strategy: MatchByRef, Normalization: DefaultNormalization})
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultNormalization, ft.Fields["foo"].Normalization)
+
+	for _, r := range []string{"Female", "female", "Female ", "FEMALE\u200b"} {
+		res, err := Translate("foo", r, ft)
+		assert.Nil(t, err)
+		assert.Equal(t, "महिला", *res)
+	}
+
+	ft, err = MakeTranslator(src, dst, &TranslatorOptions{Strategy: MatchByRef})
+	assert.Nil(t, err)
+	res, err := Translate("foo", "female", ft)
+	assert.Nil(t, err)
+	assert.Nil(t, res)
+}
+
+func TestMakeTranslatorErrorsOnNormalizationConflicts(t *testing.T) {
+	src := &Form{Fields: []*Field{{Ref: "foo", Type: "multiple_choice", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "a"}, {Label: "A"}}}}}}
+	dst := &Form{Fields: []*Field{{Ref: "foo", Type: "multiple_choice", Properties: &FieldProperties{
+		Choices: []*FieldChoice{{Label: "x"}, {Label: "y"}}}}}}
+
+	_, err := MakeTranslator(src, dst, &TranslatorOptions{Normalization: []string{NormalizeCaseFold}})
+	assert.NotNil(t, err)
+
+	_, err = MakeTranslator(src, dst, &TranslatorOptions{Normalization: []string{"soundex"}})
+	assert.NotNil(t, err)
+	assert.Equal(t, ReasonInvalidOptions, reasonOf(err, ""))
+}
@@ -0,0 +1,108 @@
+package trans
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Typeform defaults when steps is not set on the field
+var defaultSteps = map[string]int{
+	"opinion_scale": 11,
+	"rating":        5,
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func numberRange(field *Field) (*int, *int, error) {
+	switch field.Type {
+	case "nps":
+		return intPtr(0), intPtr(10), nil
+
+	case "opinion_scale", "rating":
+		steps := defaultSteps[field.Type]
+		startAtOne := field.Type == "rating"
+		if field.Properties != nil {
+			if field.Properties.Steps != 0 {
+				steps = field.Properties.Steps
+			}
+			startAtOne = startAtOne || field.Properties.StartAtOne
+		}
+		if steps < 1 {
+			return nil, nil, &FormTranslationError{Message: fmt.Sprintf("Field %v has an invalid number of steps: %v", field.Ref, steps), Reason: ReasonInvalidField}
+		}
+		if startAtOne {
+			return intPtr(1), intPtr(steps), nil
+		}
+		return intPtr(0), intPtr(steps - 1), nil
+
+	case "number":
+		if field.Validations == nil {
+			return nil, nil, nil
+		}
+		return field.Validations.MinValue, field.Validations.MaxValue, nil
+	}
+
+	return nil, nil, &FormTranslationError{Message: fmt.Sprintf("Field %v of type %v is not numeric", field.Ref, field.Type), Reason: ReasonInvalidField}
+}
+
+func MakeNumberTranslator(src *Field, dst *Field) (*FieldTranslator, error) {
+	min, max, err := numberRange(src)
+	if err != nil {
+		return nil, err
+	}
+	return &FieldTranslator{Translate: true, Kind: KindNumber, Min: min, Max: max}, nil
+}
+
+// digitValue returns the value of any unicode decimal digit,
+// relying on the fact that unicode encodes each set of decimal
+// digits contiguously, starting from zero.
+func digitValue(r rune) (int, bool) {
+	for _, rng := range unicode.Nd.R16 {
+		lo, hi := rune(rng.Lo), rune(rng.Hi)
+		if r >= lo && r <= hi {
+			return int(r-lo) % 10, true
+		}
+	}
+	for _, rng := range unicode.Nd.R32 {
+		lo, hi := rune(rng.Lo), rune(rng.Hi)
+		if r >= lo && r <= hi {
+			return int(r-lo) % 10, true
+		}
+	}
+	return 0, false
+}
+
+// NormalizeDigits converts any unicode decimal digits in s,
+// such as Devanagari, Arabic-Indic or Bengali, to ASCII digits.
+func NormalizeDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= unicode.MaxASCII {
+			return r
+		}
+		if d, ok := digitValue(r); ok {
+			return rune('0' + d)
+		}
+		return r
+	}, s)
+}
+
+func translateNumber(fieldTranslator *FieldTranslator, response string) *string {
+	n, err := strconv.Atoi(NormalizeDigits(strings.TrimSpace(response)))
+	if err != nil {
+		return nil
+	}
+
+	if fieldTranslator.Min != nil && n < *fieldTranslator.Min {
+		return nil
+	}
+	if fieldTranslator.Max != nil && n > *fieldTranslator.Max {
+		return nil
+	}
+
+	res := strconv.Itoa(n)
+	return &res
+}
@@ -0,0 +1,96 @@
+package trans
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeDigits(t *testing.T) {
+	assert.Equal(t, "42", NormalizeDigits("42"))
+	assert.Equal(t, "42", NormalizeDigits("४२"))
+	assert.Equal(t, "42", NormalizeDigits("٤٢"))
+	assert.Equal(t, "42", NormalizeDigits("۴۲"))
+	assert.Equal(t, "42", NormalizeDigits("৪২"))
+	assert.Equal(t, "1090", NormalizeDigits("१०९०"))
+	assert.Equal(t, "foo 7", NormalizeDigits("foo ७"))
+}
+
+func TestMakeFieldTranslatorTranslatesOpinionScale(t *testing.T) {
+	field := `{"id": "YmJEQUEqh0h1", "properties": {"labels": {"left": "Not at all concerned", "right": "Very concerned"}, "start_at_one": true, "steps": 5},
+                  "ref": "9ddb9864-e684-4c69-8dfe-24648ce5a6a0",
+                  "title": "How concerned are you about getting infected with COVID-19?",
+                  "type": "opinion_scale",
+                  "validations": {"required": false}}`
+
+	f := new(Field)
+	json.Unmarshal([]byte(field), f)
+
+	res, err := MakeFieldTranslator(f, f)
+	assert.Nil(t, err)
+	assert.True(t, res.Translate)
+	assert.Equal(t, KindNumber, res.Kind)
+	assert.Equal(t, 1, *res.Min)
+	assert.Equal(t, 5, *res.Max)
+}
+
+func TestMakeNumberTranslatorRanges(t *testing.T) {
+	cases := []struct {
+		field    string
+		min, max *int
+	}{
+		{`{"type": "opinion_scale", "properties": {"steps": 5}}`, intPtr(0), intPtr(4)},
+		{`{"type": "opinion_scale", "properties": {}}`, intPtr(0), intPtr(10)},
+		{`{"type": "rating", "properties": {"steps": 3}}`, intPtr(1), intPtr(3)},
+		{`{"type": "rating", "properties": {}}`, intPtr(1), intPtr(5)},
+		{`{"type": "nps", "properties": {}}`, intPtr(0), intPtr(10)},
+		{`{"type": "number", "properties": {}, "validations": {"min_value": 18, "max_value": 99}}`, intPtr(18), intPtr(99)},
+		{`{"type": "number", "properties": {}}`, nil, nil},
+	}
+
+	for _, c := range cases {
+		f := new(Field)
+		json.Unmarshal([]byte(c.field), f)
+
+		res, err := MakeNumberTranslator(f, f)
+		assert.Nil(t, err)
+		assert.Equal(t, c.min, res.Min)
+		assert.Equal(t, c.max, res.Max)
+	}
+}
+
+func TestTranslateNormalizesNumbers(t *testing.T) {
+	ft := &FormTranslator{map[string]*FieldTranslator{
+		"scale": {Translate: true, Kind: KindNumber, Min: intPtr(1), Max: intPtr(5)},
+		"age":   {Translate: true, Kind: KindNumber},
+	}}
+
+	res, err := Translate("scale", "४", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "4", *res)
+
+	res, err = Translate("scale", " ٣ ", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "3", *res)
+
+	res, err = Translate("scale", "৫", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "5", *res)
+
+	res, err = Translate("age", "१०९", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "109", *res)
+}
+
+func TestTranslateRejectsInvalidNumbers(t *testing.T) {
+	ft := &FormTranslator{map[string]*FieldTranslator{
+		"scale": {Translate: true, Kind: KindNumber, Min: intPtr(1), Max: intPtr(5)},
+	}}
+
+	for _, r := range []string{"0", "६", "10", "foo", "", "3.5"} {
+		res, err := Translate("scale", r, ft)
+		assert.Nil(t, err)
+		assert.Nil(t, res)
+	}
+}
@@ -0,0 +1,293 @@
+package trans
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// ResponseField is the field an answer of a Typeform response is to
+type ResponseField struct {
+	ID   string `json:"id,omitempty"`
+	Type string `json:"type"`
+	Ref  string `json:"ref"`
+}
+
+type ResponseChoice struct {
+	ID    string `json:"id,omitempty"`
+	Label string `json:"label,omitempty"`
+	Ref   string `json:"ref,omitempty"`
+	Other string `json:"other,omitempty"`
+}
+
+type ResponseChoices struct {
+	IDs    []string `json:"ids,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+	Refs   []string `json:"refs,omitempty"`
+	Other  string   `json:"other,omitempty"`
+}
+
+// ResponseAnswer is an answer of a Typeform response. Type says
+// which of the values is set, such as "choice" or "number".
+type ResponseAnswer struct {
+	Type        string           `json:"type"`
+	Field       *ResponseField   `json:"field"`
+	Choice      *ResponseChoice  `json:"choice,omitempty"`
+	Choices     *ResponseChoices `json:"choices,omitempty"`
+	Number      *json.Number     `json:"number,omitempty"`
+	Boolean     *bool            `json:"boolean,omitempty"`
+	Text        *string          `json:"text,omitempty"`
+	Email       *string          `json:"email,omitempty"`
+	URL         *string          `json:"url,omitempty"`
+	Date        *string          `json:"date,omitempty"`
+	PhoneNumber *string          `json:"phone_number,omitempty"`
+	FileURL     *string          `json:"file_url,omitempty"`
+	Payment     json.RawMessage  `json:"payment,omitempty"`
+}
+
+// value is the answer of the types that are
+// passed through, such as text or booleans.
+func (a *ResponseAnswer) value() string {
+	for _, v := range []*string{a.Text, a.Email, a.URL, a.Date, a.PhoneNumber, a.FileURL} {
+		if v != nil {
+			return *v
+		}
+	}
+	if a.Boolean != nil {
+		return strconv.FormatBool(*a.Boolean)
+	}
+	return ""
+}
+
+// ResponseEnding is the thank you screen the respondent ended on
+type ResponseEnding struct {
+	ID  string `json:"id,omitempty"`
+	Ref string `json:"ref"`
+}
+
+// FormResponse is a response to a Typeform form, as found in
+// webhook payloads and the items of the Responses API.
+// NOTE: the scores, variables and metadata don't depend on the
+// language of the form, so they are kept as they are.
+type FormResponse struct {
+	FormID      string            `json:"form_id,omitempty"`
+	Token       string            `json:"token,omitempty"`
+	ResponseID  string            `json:"response_id,omitempty"`
+	LandingID   string            `json:"landing_id,omitempty"`
+	LandedAt    string            `json:"landed_at,omitempty"`
+	SubmittedAt string            `json:"submitted_at,omitempty"`
+	Hidden      map[string]string `json:"hidden,omitempty"`
+	Definition  *Form             `json:"definition,omitempty"`
+	Answers     []*ResponseAnswer `json:"answers"`
+	Ending      *ResponseEnding   `json:"ending,omitempty"`
+	Calculated  json.RawMessage   `json:"calculated,omitempty"`
+	Variables   json.RawMessage   `json:"variables,omitempty"`
+	Metadata    json.RawMessage   `json:"metadata,omitempty"`
+}
+
+type WebhookPayload struct {
+	EventID      string        `json:"event_id,omitempty"`
+	EventType    string        `json:"event_type,omitempty"`
+	FormResponse *FormResponse `json:"form_response"`
+}
+
+// ResponsesPage is a page of the Typeform Responses API
+type ResponsesPage struct {
+	TotalItems int             `json:"total_items"`
+	PageCount  int             `json:"page_count"`
+	Items      []*FormResponse `json:"items"`
+}
+
+func ParseWebhookPayload(b []byte) (*WebhookPayload, error) {
+	p := new(WebhookPayload)
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, &TranslationError{fmt.Sprintf("Could not parse webhook payload: %v", err)}
+	}
+	if p.FormResponse == nil {
+		return nil, &TranslationError{"Webhook payload has no form_response!"}
+	}
+	return p, nil
+}
+
+func ParseResponsesPage(b []byte) (*ResponsesPage, error) {
+	p := new(ResponsesPage)
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, &TranslationError{fmt.Sprintf("Could not parse responses: %v", err)}
+	}
+	return p, nil
+}
+
+type answerTranslator struct {
+	ft     *FormTranslator
+	report *SubmissionReport
+}
+
+func (at *answerTranslator) translate(ref, response string) (*Translation, error) {
+	t, err := TranslateResponse(ref, response, at.ft)
+	if err := at.report.record(ref, t, err); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (at *answerTranslator) passthrough(ref, response, status string, fieldTranslator *FieldTranslator) {
+	t := &Translation{Ref: ref, DestRef: fieldTranslator.destRef(ref), Value: &response, Original: response, Status: status, FieldType: fieldTranslator.FieldType}
+	at.report.record(ref, t, nil)
+}
+
+// choiceRef is the ref of the destination choice, if known
+func choiceRef(t *Translation) string {
+	if t.Match == nil || t.Match.Choice == nil {
+		return ""
+	}
+	return t.Match.Choice.DestRef
+}
+
+// choiceLabel is the label of the destination choice, if known.
+// NOTE: the translation of a lettered choice is the text of
+// its option, rather than the label of the choice.
+func choiceLabel(t *Translation) string {
+	if t.Match == nil || t.Match.Choice == nil || t.Match.Choice.DestLabel == "" {
+		return *t.Value
+	}
+	return t.Match.Choice.DestLabel
+}
+
+// answer translates an answer into an answer to the destination
+// field, of the same type.
+// NOTE: the IDs of fields and choices are those of the source form,
+// so they are dropped, as the translator only knows the refs.
+func (at *answerTranslator) answer(a *ResponseAnswer) (*ResponseAnswer, error) {
+	if a.Field == nil {
+		return nil, &TranslationError{fmt.Sprintf("Answer of type %v has no field!", a.Type)}
+	}
+	ref := a.Field.Ref
+
+	fieldTranslator, err := getFieldTranslator(ref, at.ft)
+	if err != nil {
+		return nil, at.report.record(ref, nil, err)
+	}
+
+	res := *a
+	res.Field = &ResponseField{Type: a.Field.Type, Ref: fieldTranslator.destRef(ref)}
+
+	switch {
+	case a.Choice != nil:
+		c := &ResponseChoice{Other: a.Choice.Other}
+		if a.Choice.Label != "" {
+			t, err := at.translate(ref, a.Choice.Label)
+			if err != nil {
+				return nil, err
+			}
+			c.Label, c.Ref = choiceLabel(t), choiceRef(t)
+		}
+		if c.Other != "" {
+			at.passthrough(ref, c.Other, StatusOther, fieldTranslator)
+		}
+		res.Choice = c
+
+	case a.Choices != nil:
+		c := &ResponseChoices{Other: a.Choices.Other}
+		for _, l := range a.Choices.Labels {
+			t, err := at.translate(ref, l)
+			if err != nil {
+				return nil, err
+			}
+			c.Labels = append(c.Labels, choiceLabel(t))
+			if r := choiceRef(t); r != "" {
+				c.Refs = append(c.Refs, r)
+			}
+		}
+
+		// refs are only useful if every choice has one
+		if len(c.Refs) != len(c.Labels) {
+			c.Refs = nil
+		}
+		if c.Other != "" {
+			at.passthrough(ref, c.Other, StatusOther, fieldTranslator)
+		}
+		res.Choices = c
+
+	case a.Number != nil:
+		t, err := at.translate(ref, a.Number.String())
+		if err != nil {
+			return nil, err
+		}
+		n := json.Number(*t.Value)
+		res.Number = &n
+
+	case fieldTranslator.Translate && (a.Text != nil || a.Boolean != nil):
+		// chat respondents answer choice and yes_no
+		// fields with text, which is translated as such
+		t, err := at.translate(ref, a.value())
+		if err != nil {
+			return nil, err
+		}
+		if a.Boolean != nil {
+			b, err := strconv.ParseBool(*t.Value)
+			if err != nil {
+				return nil, &TranslationError{fmt.Sprintf("Response %v to ref %v did not translate to a boolean: %v", a.value(), ref, *t.Value)}
+			}
+			res.Boolean = &b
+		} else {
+			res.Text = t.Value
+		}
+
+	default:
+		// other types of answers, such as emails or dates,
+		// don't depend on the language of the form
+		at.passthrough(ref, a.value(), StatusPassthrough, fieldTranslator)
+	}
+
+	return &res, nil
+}
+
+// TranslateFormResponse translates every answer of a Typeform response
+// into an answer to the destination form, keeping the types of the
+// answers, and reports on them as TranslateAnswers does. The definition
+// of the source form is dropped from the result, as are the IDs of
+// its fields, choices and ending.
+func TranslateFormResponse(r *FormResponse, ft *FormTranslator, opts *SubmissionOptions) (*FormResponse, *SubmissionReport, error) {
+	if opts == nil {
+		opts = &SubmissionOptions{}
+	}
+
+	at := &answerTranslator{ft, &SubmissionReport{}}
+	res := *r
+	res.Definition = nil
+	res.Answers = []*ResponseAnswer{}
+
+	if r.Ending != nil {
+		res.Ending = &ResponseEnding{Ref: r.Ending.Ref}
+		if f, ok := ft.Fields[r.Ending.Ref]; ok {
+			res.Ending.Ref = f.destRef(r.Ending.Ref)
+		}
+	}
+	errs := []error{}
+
+	for _, a := range r.Answers {
+		ta, err := at.answer(a)
+		if err != nil {
+			errs = append(errs, err)
+			if opts.FailFast {
+				return &res, at.report, err
+			}
+			continue
+		}
+		res.Answers = append(res.Answers, ta)
+	}
+
+	if len(errs) > 0 {
+		return &res, at.report, &SubmissionErrors{errs}
+	}
+	return &res, at.report, nil
+}
+
+func TranslatePayload(p *WebhookPayload, ft *FormTranslator, opts *SubmissionOptions) (*WebhookPayload, *SubmissionReport, error) {
+	if p.FormResponse == nil {
+		return nil, nil, &TranslationError{"Webhook payload has no form_response!"}
+	}
+
+	r, report, err := TranslateFormResponse(p.FormResponse, ft, opts)
+	return &WebhookPayload{p.EventID, p.EventType, r}, report, err
+}
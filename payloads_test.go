@@ -0,0 +1,222 @@
+package trans
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const webhookPayload = `{
+  "event_id": "01F5Y6XW",
+  "event_type": "form_response",
+  "form_response": {
+    "form_id": "lT4Z3j",
+    "token": "a3a12ec67a1365927098a606107fac15",
+    "submitted_at": "2021-05-18T07:21:06Z",
+    "landed_at": "2021-05-18T07:20:31Z",
+    "hidden": {"seed": "3"},
+    "definition": {"title": "Survey", "fields": [{"id": "Z5", "ref": "gender", "type": "multiple_choice", "title": "Gender?"}]},
+    "answers": [
+      {"type": "choice", "choice": {"id": "c1", "label": "Female", "ref": "f"},
+       "field": {"id": "Z5", "type": "multiple_choice", "ref": "gender"}},
+      {"type": "choices", "choices": {"labels": ["Gas", "Wood"], "other": "Dung"},
+       "field": {"id": "Z6", "type": "multiple_choice", "ref": "fuel"}},
+      {"type": "number", "number": 4,
+       "field": {"id": "Z7", "type": "rating", "ref": "happy"}},
+      {"type": "boolean", "boolean": true,
+       "field": {"id": "Z8", "type": "yes_no", "ref": "consent"}},
+      {"type": "text", "text": "Asha",
+       "field": {"id": "Z9", "type": "short_text", "ref": "name"}}
+    ]
+  }
+}`
+
+func getPayloadForms() (*Form, *Form) {
+	src := &Form{Fields: []*Field{
+		{Ref: "gender", Type: "multiple_choice", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "Female", Ref: "f"}, {Label: "Male", Ref: "m"}}}},
+		{Ref: "fuel", Type: "multiple_choice", Properties: &FieldProperties{
+			AllowMultipleSelection: true, AllowOtherChoice: true,
+			Choices: []*FieldChoice{{Label: "Gas"}, {Label: "Wood"}}}},
+		{Ref: "happy", Type: "rating"},
+		{Ref: "consent", Type: "yes_no"},
+		{Ref: "name", Type: "short_text"},
+	}}
+	dst := &Form{Fields: []*Field{
+		{Ref: "gender", Type: "multiple_choice", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "महिला", Ref: "f"}, {Label: "पुरुष", Ref: "m"}}}},
+		{Ref: "fuel", Type: "multiple_choice", Properties: &FieldProperties{
+			AllowMultipleSelection: true, AllowOtherChoice: true,
+			Choices: []*FieldChoice{{Label: "गैस"}, {Label: "लकड़ी"}}}},
+		{Ref: "happy", Type: "rating"},
+		{Ref: "consent", Type: "yes_no"},
+		{Ref: "name", Type: "short_text"},
+	}}
+	return src, dst
+}
+
+func TestTranslatePayload(t *testing.T) {
+	p, err := ParseWebhookPayload([]byte(webhookPayload))
+	assert.Nil(t, err)
+	assert.Equal(t, "Female", p.FormResponse.Answers[0].Choice.Label)
+	assert.Equal(t, "4", p.FormResponse.Answers[2].Number.String())
+
+	src, dst := getPayloadForms()
+	ft, err := MakeTranslatorByRef(src, dst)
+	assert.Nil(t, err)
+
+	res, report, err := TranslatePayload(p, ft, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "form_response", res.EventType)
+	assert.Nil(t, res.FormResponse.Definition)
+	assert.Equal(t, "3", res.FormResponse.Hidden["seed"])
+
+	answers := res.FormResponse.Answers
+	assert.Equal(t, &ResponseChoice{Label: "महिला", Ref: "f"}, answers[0].Choice)
+	assert.Equal(t, &ResponseField{Type: "multiple_choice", Ref: "gender"}, answers[0].Field)
+	assert.Equal(t, []string{"गैस", "लकड़ी"}, answers[1].Choices.Labels)
+	assert.Equal(t, "Dung", answers[1].Choices.Other)
+	assert.Equal(t, "4", answers[2].Number.String())
+	assert.True(t, *answers[3].Boolean)
+	assert.Equal(t, "Asha", *answers[4].Text)
+
+	assert.Equal(t, []string{"fuel"}, report.Other)
+	assert.Equal(t, []string{"name"}, report.Passthrough)
+
+	// answer types are kept when written back out
+	b, err := json.Marshal(res)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), `"number":4`)
+	assert.Contains(t, string(b), `"boolean":true`)
+}
+
+func TestTranslatePayloadReportsInvalidAnswers(t *testing.T) {
+	p, err := ParseWebhookPayload([]byte(webhookPayload))
+	assert.Nil(t, err)
+	p.FormResponse.Answers[0].Choice.Label = "Unknown"
+	*p.FormResponse.Answers[2].Number = "9"
+
+	src, dst := getPayloadForms()
+	ft, err := MakeTranslatorByRef(src, dst)
+	assert.Nil(t, err)
+	delete(ft.Fields, "name")
+
+	res, report, err := TranslatePayload(p, ft, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, len(res.FormResponse.Answers))
+	assert.Equal(t, []string{"gender", "happy"}, report.Unknown)
+	assert.Equal(t, []string{"name"}, report.UnknownRefs)
+
+	_, report, err = TranslatePayload(p, ft, &SubmissionOptions{FailFast: true})
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"gender"}, report.Unknown)
+}
+
+func TestParseResponsesPage(t *testing.T) {
+	page := `{"total_items": 1, "page_count": 1, "items": [
+      {"landing_id": "21085286190ffad1248d17c4135ee56f", "token": "21085286190ffad1248d17c4135ee56f",
+       "answers": [{"type": "choice", "choice": {"label": "Male"}, "field": {"id": "Z5", "type": "multiple_choice", "ref": "gender"}}]}]}`
+
+	p, err := ParseResponsesPage([]byte(page))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(p.Items))
+
+	src, dst := getPayloadForms()
+	ft, err := MakeTranslatorByRef(src, dst)
+	assert.Nil(t, err)
+
+	res, _, err := TranslateFormResponse(p.Items[0], ft, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "पुरुष", res.Answers[0].Choice.Label)
+
+	_, err = ParseWebhookPayload([]byte(`{"event_id": "foo"}`))
+	assert.NotNil(t, err)
+}
+
+func TestTranslatePayloadKeepsScoresAndMetadata(t *testing.T) {
+	payload := `{"event_id": "01F5Y6XW", "event_type": "form_response", "form_response": {
+      "form_id": "lT4Z3j",
+      "calculated": {"score": 9},
+      "variables": [{"key": "score", "type": "number", "number": 9}, {"key": "name", "type": "text", "text": "typeform"}],
+      "metadata": {"platform": "other", "network_id": "5d1c6ae0c0"},
+      "ending": {"id": "e1", "ref": "tys"},
+      "answers": [
+        {"type": "payment", "payment": {"amount": "1.00", "last4": "4242", "name": "Asha", "success": true},
+         "field": {"id": "Z9", "type": "payment", "ref": "name"}}]}}`
+
+	p, err := ParseWebhookPayload([]byte(payload))
+	assert.Nil(t, err)
+
+	ft := &FormTranslator{map[string]*FieldTranslator{
+		"name": {Translate: false},
+		"tys":  {Translate: false, DestRef: "eng_tys"},
+	}}
+
+	res, _, err := TranslatePayload(p, ft, nil)
+	assert.Nil(t, err)
+
+	b, err := json.Marshal(res)
+	assert.Nil(t, err)
+	out := string(b)
+	assert.Contains(t, out, `"calculated":{"score":9}`)
+	assert.Contains(t, out, `{"key":"score","type":"number","number":9}`)
+	assert.Contains(t, out, `"network_id":"5d1c6ae0c0"`)
+	assert.Contains(t, out, `"last4":"4242"`)
+	assert.Equal(t, &ResponseEnding{Ref: "eng_tys"}, res.FormResponse.Ending)
+}
+
+func TestTranslatePayloadTranslatesTextAnswers(t *testing.T) {
+	src, dst := getPayloadForms()
+	ft, err := MakeTranslator(src, dst, &TranslatorOptions{Strategy: MatchByRef, FuzzyMatching: true})
+	assert.Nil(t, err)
+
+	yes, female, other := "हाँ", "female", "Dung"
+	r := &FormResponse{Answers: []*ResponseAnswer{
+		{Type: "text", Text: &yes, Field: &ResponseField{Type: "yes_no", Ref: "consent"}},
+		{Type: "text", Text: &female, Field: &ResponseField{Type: "multiple_choice", Ref: "gender"}},
+		{Type: "text", Text: &other, Field: &ResponseField{Type: "short_text", Ref: "name"}},
+	}}
+
+	res, report, err := TranslateFormResponse(r, ft, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, BooleanTrue, *res.Answers[0].Text)
+	assert.Equal(t, "महिला", *res.Answers[1].Text)
+	assert.Equal(t, "Dung", *res.Answers[2].Text)
+	assert.Equal(t, []string{"name"}, report.Passthrough)
+
+	nope := "Maybe"
+	r.Answers[0].Text = &nope
+	_, report, err = TranslateFormResponse(r, ft, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"consent"}, report.Unknown)
+}
+
+func TestTranslatePayloadWritesDestinationLabelsOfLetteredChoices(t *testing.T) {
+	src := &Form{Fields: []*Field{
+		{Ref: "state", Type: "multiple_choice", Title: "State?\nA. Jharkhand\nB. Odisha", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "A", Ref: "a"}, {Label: "B", Ref: "b"}}}},
+		{Ref: "states", Type: "multiple_choice", Title: "States?\nA. Jharkhand\nB. Odisha", Properties: &FieldProperties{
+			AllowMultipleSelection: true,
+			Choices:                []*FieldChoice{{Label: "A", Ref: "a"}, {Label: "B", Ref: "b"}}}},
+	}}
+	dst := &Form{Fields: []*Field{
+		{Ref: "state", Type: "multiple_choice", Title: "राज्य?\nA. झारखंड\nB. ओडिशा", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "A", Ref: "a"}, {Label: "B", Ref: "b"}}}},
+		{Ref: "states", Type: "multiple_choice", Title: "राज्य?\nA. झारखंड\nB. ओडिशा", Properties: &FieldProperties{
+			AllowMultipleSelection: true,
+			Choices:                []*FieldChoice{{Label: "A", Ref: "a"}, {Label: "B", Ref: "b"}}}},
+	}}
+	ft, err := MakeTranslatorByRef(src, dst)
+	assert.Nil(t, err)
+
+	r := &FormResponse{Answers: []*ResponseAnswer{
+		{Type: "choice", Choice: &ResponseChoice{Label: "B"}, Field: &ResponseField{Type: "multiple_choice", Ref: "state"}},
+		{Type: "choices", Choices: &ResponseChoices{Labels: []string{"A", "B"}}, Field: &ResponseField{Type: "multiple_choice", Ref: "states"}},
+	}}
+
+	res, _, err := TranslateFormResponse(r, ft, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, &ResponseChoice{Label: "B", Ref: "b"}, res.Answers[0].Choice)
+	assert.Equal(t, &ResponseChoices{Labels: []string{"A", "B"}, Refs: []string{"a", "b"}}, res.Answers[1].Choices)
+}
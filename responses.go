@@ -1,6 +1,9 @@
 package trans
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type TranslationError struct {
 	Message string
@@ -10,23 +13,173 @@ func (e *TranslationError) Error() string {
 	return e.Message
 }
 
-func Translate(qr, response string, ft *FormTranslator) (*string, error) {
+// Separator used when multiple selections arrive joined in a single string
+const MultipleSelectionSeparator = ","
 
+func getFieldTranslator(qr string, ft *FormTranslator) (*FieldTranslator, error) {
 	fieldTranslator, ok := ft.Fields[qr]
 	if !ok {
 		return nil, &TranslationError{fmt.Sprintf("Ref %v not found in translation mapping!", qr)}
 	}
+	return fieldTranslator, nil
+}
+
+// Statuses of a Translation
+const (
+	// the response was translated
+	StatusTranslated = "translated"
+
+	// the field is not translated, so the response is passed through
+	StatusPassthrough = "passthrough"
+
+	// the response is not a valid answer to the field
+	StatusUnknown = "unknown_answer"
+
+	// the response is the free text of a field that allows "other",
+	// which is passed through untranslated
+	StatusOther = "other_text"
+)
+
+// translators made by hand might not know the destination
+func (ft *FieldTranslator) destRef(qr string) string {
+	if ft.DestRef == "" {
+		return qr
+	}
+	return ft.DestRef
+}
+
+// Translation is the result of translating a response to the field
+// Ref, into a response to the field DestRef. Value is nil when the
+// response is not a valid answer. Match is the choice the response
+// was matched to, if the field has choices and the response is a
+// single selection.
+type Translation struct {
+	Ref       string
+	DestRef   string
+	Value     *string
+	Original  string
+	Status    string
+	FieldType string
+	Match     *ChoiceMatch
+}
+
+// Translate returns the translated response, the original response
+// if the field is not translated, or nil if it is not a valid answer.
+// Use TranslateResponse to know which.
+func Translate(qr, response string, ft *FormTranslator) (*string, error) {
+	t, err := TranslateResponse(qr, response, ft)
+	if err != nil {
+		return nil, err
+	}
+	return t.Value, nil
+}
+
+func TranslateResponse(qr, response string, ft *FormTranslator) (*Translation, error) {
+
+	fieldTranslator, err := getFieldTranslator(qr, ft)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Translation{Ref: qr, DestRef: fieldTranslator.destRef(qr), Original: response, FieldType: fieldTranslator.FieldType}
+	result := func(status string, value *string) (*Translation, error) {
+		res.Status, res.Value = status, value
+		return res, nil
+	}
 
 	// If not translate, return original message
 	if !fieldTranslator.Translate {
-		return &response, nil
+		return result(StatusPassthrough, &response)
 	}
 
 	// If not valid answer, dont error, just dont translate
-	translated, ok := fieldTranslator.Mapping[response]
-	if !ok {
-		return nil, nil
+	value, m, status := fieldTranslator.translateSelection(response)
+	if status == StatusTranslated || !fieldTranslator.MultipleSelection {
+		res.Match = m
+		return result(status, value)
+	}
+
+	// NOTE: a label can itself contain the separator, so we only
+	// split after the full response failed to match.
+	selections := strings.Split(response, MultipleSelectionSeparator)
+	translated := 0
+	for i, s := range selections {
+		v, _, st := fieldTranslator.translateSelection(strings.TrimSpace(s))
+		if st == StatusUnknown {
+			return result(StatusUnknown, nil)
+		}
+		if st == StatusTranslated {
+			translated++
+		}
+		selections[i] = *v
+	}
+
+	// other text that merely contains the separator
+	// is the respondent's, so is kept as it is
+	if translated == 0 {
+		return result(StatusOther, &response)
+	}
+
+	status = StatusTranslated
+	if translated < len(selections) {
+		status = StatusOther
+	}
+	joined := strings.Join(selections, MultipleSelectionSeparator)
+	return result(status, &joined)
+}
+
+// translateSelection translates a single selection, returning
+// the choice it was matched to, if any, and its status.
+func (ft *FieldTranslator) translateSelection(response string) (*string, *ChoiceMatch, string) {
+	if ft.Kind == KindNumber {
+		if n := translateNumber(ft, response); n != nil {
+			return n, nil, StatusTranslated
+		}
+		return nil, nil, StatusUnknown
+	}
+
+	if m, ok := ft.matchExact(response); ok {
+		return &m.Value, m, StatusTranslated
+	}
+
+	if ft.Fuzzy {
+		if m, ok := ft.fuzzyMatch(strings.TrimSpace(response)); ok {
+			return &m.Value, m, StatusTranslated
+		}
+	}
+
+	if ft.AllowOther {
+		return &response, nil, StatusOther
+	}
+	return nil, nil, StatusUnknown
+}
+
+// TranslateMultiple translates each selection of a multi-select
+// response, as TranslateResponse does, returning the translated
+// selections along with the selections that could not be translated.
+// Other text, on fields that allow it, is passed through.
+func TranslateMultiple(qr string, responses []string, ft *FormTranslator) ([]string, []string, error) {
+
+	fieldTranslator, err := getFieldTranslator(qr, ft)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !fieldTranslator.Translate {
+		return responses, nil, nil
+	}
+
+	translated := []string{}
+	failed := []string{}
+
+	for _, r := range responses {
+		t, _, status := fieldTranslator.translateSelection(strings.TrimSpace(r))
+		if status == StatusUnknown {
+			failed = append(failed, r)
+			continue
+		}
+		translated = append(translated, *t)
 	}
 
-	return &translated, nil
+	return translated, failed, nil
 }
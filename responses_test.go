@@ -8,13 +8,13 @@ import (
 func TestTranslateWorksWithGoodData(t *testing.T) {
 
 	ft := &FormTranslator{map[string]*FieldTranslator{
-		"foo": {true, map[string]string{
+		"foo": {Translate: true, Mapping: map[string]string{
 			"A": "Makin that monay",
 		}},
-		"bar": {true, map[string]string{
+		"bar": {Translate: true, Mapping: map[string]string{
 			"man": "hombre",
 		}},
-		"baz": {false, map[string]string{}},
+		"baz": {Translate: false, Mapping: map[string]string{}},
 	}}
 
 	res, err := Translate("foo", "A", ft)
@@ -32,7 +32,7 @@ func TestTranslateWorksWithGoodData(t *testing.T) {
 
 func TestTranslateReturnsNilIfInvalidAnswer(t *testing.T) {
 	ft := &FormTranslator{map[string]*FieldTranslator{
-		"foo": {true, map[string]string{
+		"foo": {Translate: true, Mapping: map[string]string{
 			"A": "Makin that monay",
 		}},
 	}}
@@ -44,7 +44,7 @@ func TestTranslateReturnsNilIfInvalidAnswer(t *testing.T) {
 
 func TestTranslateErrorsIfImpossibleRef(t *testing.T) {
 	ft := &FormTranslator{map[string]*FieldTranslator{
-		"foo": {true, map[string]string{
+		"foo": {Translate: true, Mapping: map[string]string{
 			"A": "Makin that monay",
 		}},
 	}}
@@ -53,3 +53,179 @@ func TestTranslateErrorsIfImpossibleRef(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Nil(t, res)
 }
+
+func TestTranslateMultipleSelectionTranslatesEachSelection(t *testing.T) {
+	ft := &FormTranslator{map[string]*FieldTranslator{
+		"foo": {Translate: true, MultipleSelection: true, Mapping: map[string]string{
+			"कुत्ता": "dog",
+			"बिल्ली": "cat",
+			"गाय":    "cow",
+		}},
+	}}
+
+	res, err := Translate("foo", "कुत्ता, गाय", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "dog,cow", *res)
+
+	res, err = Translate("foo", "बिल्ली", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "cat", *res)
+
+	res, err = Translate("foo", "कुत्ता, घोड़ा", ft)
+	assert.Nil(t, err)
+	assert.Nil(t, res)
+}
+
+func TestTranslateDoesntSplitSingleSelection(t *testing.T) {
+	ft := &FormTranslator{map[string]*FieldTranslator{
+		"foo": {Translate: true, Mapping: map[string]string{
+			"dog": "perro",
+			"cat": "gato",
+		}},
+	}}
+
+	res, err := Translate("foo", "dog, cat", ft)
+	assert.Nil(t, err)
+	assert.Nil(t, res)
+}
+
+func TestTranslateMultipleSelectionMatchesLabelsWithSeparator(t *testing.T) {
+	ft := &FormTranslator{map[string]*FieldTranslator{
+		"foo": {Translate: true, MultipleSelection: true, Mapping: map[string]string{
+			"Yes, often": "Sí, a menudo",
+		}},
+	}}
+
+	res, err := Translate("foo", "Yes, often", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "Sí, a menudo", *res)
+}
+
+func TestTranslateMultipleReportsFailedSelections(t *testing.T) {
+	ft := &FormTranslator{map[string]*FieldTranslator{
+		"foo": {Translate: true, MultipleSelection: true, Mapping: map[string]string{
+			"कुत्ता": "dog",
+			"गाय":    "cow",
+		}},
+		"bar": {Translate: false},
+	}}
+
+	res, failed, err := TranslateMultiple("foo", []string{"कुत्ता", "घोड़ा", "गाय", "बकरी"}, ft)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"dog", "cow"}, res)
+	assert.Equal(t, []string{"घोड़ा", "बकरी"}, failed)
+
+	res, failed, err = TranslateMultiple("bar", []string{"anything", "else"}, ft)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"anything", "else"}, res)
+	assert.Equal(t, 0, len(failed))
+
+	_, _, err = TranslateMultiple("baz", []string{"कुत्ता"}, ft)
+	assert.NotNil(t, err)
+}
+
+func TestTranslateMultipleTranslatesLikeTranslateResponse(t *testing.T) {
+	ft := &FormTranslator{map[string]*FieldTranslator{
+		"foo": {Translate: true, MultipleSelection: true, Fuzzy: true, AllowOther: true, Mapping: map[string]string{
+			"कुत्ता": "dog",
+			"गाय":    "cow",
+		}},
+		"bar": {Translate: true, Kind: KindNumber},
+	}}
+
+	res, failed, err := TranslateMultiple("foo", []string{" कुत्ता ", "गाय.", "बकरी"}, ft)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"dog", "cow", "बकरी"}, res)
+	assert.Equal(t, 0, len(failed))
+
+	res, failed, err = TranslateMultiple("bar", []string{"५", "five"}, ft)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"5"}, res)
+	assert.Equal(t, []string{"five"}, failed)
+}
+
+func TestTranslatePassesThroughOtherText(t *testing.T) {
+	ft := &FormTranslator{map[string]*FieldTranslator{
+		"foo": {Translate: true, AllowOther: true, Mapping: map[string]string{
+			"गैस": "Gas",
+		}},
+		"bar": {Translate: true, AllowOther: true, MultipleSelection: true, Mapping: map[string]string{
+			"गैस":   "Gas",
+			"लकड़ी": "Wood",
+		}},
+	}}
+
+	res, err := TranslateResponse("foo", "गैस", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "Gas", *res.Value)
+	assert.Equal(t, StatusTranslated, res.Status)
+
+	res, err = TranslateResponse("foo", "गोबर", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "गोबर", *res.Value)
+	assert.Equal(t, StatusOther, res.Status)
+
+	s, err := Translate("foo", "गोबर", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "गोबर", *s)
+
+	res, err = TranslateResponse("bar", "लकड़ी, गोबर", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "Wood,गोबर", *res.Value)
+	assert.Equal(t, StatusOther, res.Status)
+
+	res, err = TranslateResponse("bar", "rock, paper", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, "rock, paper", *res.Value)
+	assert.Equal(t, StatusOther, res.Status)
+}
+
+func TestTranslateResponseReportsStatus(t *testing.T) {
+	src := &Form{Fields: []*Field{
+		{Ref: "foo", Type: "multiple_choice", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "Female", Ref: "f"}, {Label: "Male", Ref: "m"}}}},
+		{Ref: "bar", Type: "short_text"},
+		{Ref: "baz", Type: "rating"},
+	}}
+	dst := &Form{Fields: []*Field{
+		{Ref: "foo", Type: "multiple_choice", Properties: &FieldProperties{
+			Choices: []*FieldChoice{{Label: "महिला", Ref: "f"}, {Label: "पुरुष", Ref: "m"}}}},
+		{Ref: "bar", Type: "short_text"},
+		{Ref: "baz", Type: "rating"},
+	}}
+
+	ft, err := MakeTranslatorByRef(src, dst)
+	assert.Nil(t, err)
+
+	res, err := TranslateResponse("foo", "Male", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusTranslated, res.Status)
+	assert.Equal(t, "पुरुष", *res.Value)
+	assert.Equal(t, "Male", res.Original)
+	assert.Equal(t, "multiple_choice", res.FieldType)
+	assert.Equal(t, "m", res.Match.Choice.DestRef)
+
+	res, err = TranslateResponse("foo", "Other", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusUnknown, res.Status)
+	assert.Nil(t, res.Value)
+	assert.Nil(t, res.Match)
+
+	res, err = TranslateResponse("bar", "hello", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusPassthrough, res.Status)
+	assert.Equal(t, "hello", *res.Value)
+	assert.Equal(t, "short_text", res.FieldType)
+
+	res, err = TranslateResponse("baz", "४", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusTranslated, res.Status)
+	assert.Equal(t, "4", *res.Value)
+
+	res, err = TranslateResponse("baz", "9", ft)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusUnknown, res.Status)
+
+	_, err = TranslateResponse("qux", "9", ft)
+	assert.NotNil(t, err)
+}
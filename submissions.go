@@ -0,0 +1,122 @@
+package trans
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SubmissionAnswer is the response to the field Ref in a submission
+type SubmissionAnswer struct {
+	Ref      string `json:"ref"`
+	Response string `json:"response"`
+}
+
+// SubmissionOptions configure how a submission is translated.
+// With FailFast, translation stops at the first answer that
+// can't be translated, rather than collecting them all.
+type SubmissionOptions struct {
+	FailFast bool
+}
+
+// SubmissionReport is what happened to each answer of a submission.
+// UnknownRefs are refs that are not in the translator, Unknown are
+// refs whose answers are not valid, Passthrough are refs of fields
+// that are not translated and Other are refs answered with the free
+// text of an "other" choice.
+type SubmissionReport struct {
+	Translations []*Translation
+	UnknownRefs  []string
+	Unknown      []string
+	Passthrough  []string
+	Other        []string
+}
+
+// record adds the translation of an answer to the field ref to the
+// report, returning an error if the answer could not be translated.
+func (r *SubmissionReport) record(ref string, t *Translation, err error) error {
+	if err != nil {
+		r.UnknownRefs = append(r.UnknownRefs, ref)
+		return err
+	}
+	r.Translations = append(r.Translations, t)
+
+	switch t.Status {
+	case StatusUnknown:
+		r.Unknown = append(r.Unknown, ref)
+		return &TranslationError{fmt.Sprintf("Response %v to ref %v is not a valid answer!", t.Original, ref)}
+	case StatusPassthrough:
+		r.Passthrough = append(r.Passthrough, ref)
+	case StatusOther:
+		r.Other = append(r.Other, ref)
+	}
+	return nil
+}
+
+// SubmissionErrors collects every answer of a submission that
+// could not be translated.
+type SubmissionErrors struct {
+	Errors []error
+}
+
+func (e *SubmissionErrors) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("Could not translate %v answers:\n%v", len(e.Errors), strings.Join(msgs, "\n"))
+}
+
+// TranslateAnswers translates each answer of a submission, returning
+// the answers keyed by the refs of the destination form, in the same
+// order, without the answers that could not be translated.
+func TranslateAnswers(answers []*SubmissionAnswer, ft *FormTranslator, opts *SubmissionOptions) ([]*SubmissionAnswer, *SubmissionReport, error) {
+	if opts == nil {
+		opts = &SubmissionOptions{}
+	}
+
+	translated := []*SubmissionAnswer{}
+	report := &SubmissionReport{}
+	errs := []error{}
+
+	for _, a := range answers {
+		t, err := TranslateResponse(a.Ref, a.Response, ft)
+		if err := report.record(a.Ref, t, err); err != nil {
+			errs = append(errs, err)
+			if opts.FailFast {
+				return translated, report, err
+			}
+			continue
+		}
+
+		translated = append(translated, &SubmissionAnswer{t.DestRef, *t.Value})
+	}
+
+	if len(errs) > 0 {
+		return translated, report, &SubmissionErrors{errs}
+	}
+	return translated, report, nil
+}
+
+// TranslateSubmission translates a submission of responses by ref.
+// See TranslateAnswers.
+func TranslateSubmission(submission map[string]string, ft *FormTranslator, opts *SubmissionOptions) (map[string]string, *SubmissionReport, error) {
+	refs := make([]string, 0, len(submission))
+	for ref := range submission {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	answers := make([]*SubmissionAnswer, len(refs))
+	for i, ref := range refs {
+		answers[i] = &SubmissionAnswer{ref, submission[ref]}
+	}
+
+	translated, report, err := TranslateAnswers(answers, ft, opts)
+
+	res := make(map[string]string, len(translated))
+	for _, a := range translated {
+		res[a.Ref] = a.Response
+	}
+	return res, report, err
+}
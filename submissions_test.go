@@ -0,0 +1,60 @@
+package trans
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getSubmissionTranslator() *FormTranslator {
+	return &FormTranslator{map[string]*FieldTranslator{
+		"gender": {Translate: true, Kind: KindMapping, DestRef: "gender_hi", Mapping: map[string]string{
+			"Female": "महिला",
+			"Male":   "पुरुष",
+		}},
+		"fuel": {Translate: true, Kind: KindMapping, AllowOther: true, Mapping: map[string]string{
+			"Gas": "गैस",
+		}},
+		"name": {Translate: false},
+	}}
+}
+
+func TestTranslateSubmission(t *testing.T) {
+	ft := getSubmissionTranslator()
+
+	sub := map[string]string{"gender": "Female", "fuel": "Dung", "name": "Asha"}
+	res, report, err := TranslateSubmission(sub, ft, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"gender_hi": "महिला", "fuel": "Dung", "name": "Asha"}, res)
+	assert.Equal(t, []string{"name"}, report.Passthrough)
+	assert.Equal(t, []string{"fuel"}, report.Other)
+	assert.Equal(t, 3, len(report.Translations))
+}
+
+func TestTranslateSubmissionCollectsErrors(t *testing.T) {
+	ft := getSubmissionTranslator()
+
+	sub := map[string]string{"gender": "Unknown", "age": "30", "name": "Asha"}
+	res, report, err := TranslateSubmission(sub, ft, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, map[string]string{"name": "Asha"}, res)
+	assert.Equal(t, []string{"age"}, report.UnknownRefs)
+	assert.Equal(t, []string{"gender"}, report.Unknown)
+
+	errs, ok := err.(*SubmissionErrors)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(errs.Errors))
+}
+
+func TestTranslateAnswersFailsFast(t *testing.T) {
+	ft := getSubmissionTranslator()
+
+	answers := []*SubmissionAnswer{{"name", "Asha"}, {"gender", "Unknown"}, {"age", "30"}}
+	res, report, err := TranslateAnswers(answers, ft, &SubmissionOptions{FailFast: true})
+	assert.NotNil(t, err)
+	_, ok := err.(*TranslationError)
+	assert.True(t, ok)
+	assert.Equal(t, []*SubmissionAnswer{{"name", "Asha"}}, res)
+	assert.Equal(t, []string{"gender"}, report.Unknown)
+	assert.Nil(t, report.UnknownRefs)
+}
@@ -0,0 +1,51 @@
+package trans
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Formatting and placeholders Typeform allows in question text
+var (
+	boldPattern        = regexp.MustCompile(`\*{1,2}([^*\n]+?)\*{1,2}`)
+	italicPattern      = regexp.MustCompile(`(^|[^\p{L}\p{N}_])_([^_\n]+?)_($|[^\p{L}\p{N}_])`)
+	placeholderPattern = regexp.MustCompile(`\{\{\s*([a-z_]+):([^{}\s]+)\s*\}\}`)
+)
+
+func normalizeLine(line string, variables map[string]string) string {
+	res := boldPattern.ReplaceAllString(line, "$1")
+
+	// NOTE: the delimiters around italics are part of the match,
+	// so italics separated by a single character need a second pass.
+	for i := 0; i < 2; i++ {
+		res = italicPattern.ReplaceAllString(res, "${1}${2}${3}")
+	}
+
+	replaced := false
+	res = placeholderPattern.ReplaceAllStringFunc(res, func(p string) string {
+		m := placeholderPattern.FindStringSubmatch(p)
+		replaced = true
+		v := variables[m[1]+":"+m[2]]
+		return strings.ReplaceAll(v, "\n", " ")
+	})
+
+	// placeholders at the end of a line leave the space before them
+	if replaced {
+		res = strings.TrimRight(res, " \t")
+	}
+	return res
+}
+
+// NormalizeText removes the bold and italic formatting of Typeform
+// question text and replaces its recall placeholders, such as
+// {{field:ref}} or {{hidden:name}}, with their value in variables,
+// keyed without the braces, e.g. "hidden:name". Placeholders without
+// a value are removed. Lines are kept as they are, so that each line
+// of the result comes from the same line of the text.
+func NormalizeText(text string, variables map[string]string) string {
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		lines[i] = normalizeLine(l, variables)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,33 @@
+package trans
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTextRemovesFormatting(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		{"A. *Very* difficult", "A. Very difficult"},
+		{"A. **Very** difficult", "A. Very difficult"},
+		{"B. _Quite_ easy", "B. Quite easy"},
+		{"_a_ _b_", "a b"},
+		{"snake_case_word", "snake_case_word"},
+		{"A. foo\n*B. bar*", "A. foo\nB. bar"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, NormalizeText(c.text, nil))
+	}
+}
+
+func TestNormalizeTextResolvesPlaceholders(t *testing.T) {
+	vars := map[string]string{"hidden:name": "Asha", "field:district": "Ranchi"}
+
+	assert.Equal(t, "Hello Asha, do you live in Ranchi?", NormalizeText("Hello {{hidden:name}}, do you live in {{field:district}}?", vars))
+	assert.Equal(t, "A. I live in", NormalizeText("A. I live in {{field:state}}", vars))
+	assert.Equal(t, "A. Ranchi\nB. other", NormalizeText("A. {{ field:district }}\nB. other", vars))
+}